@@ -0,0 +1,127 @@
+package frain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultAliases maps the built-in short names to the services they stand for
+var DefaultAliases = map[string]string{
+	"cci": "circleci",
+	"dd":  "datadog",
+	"gh":  "github",
+}
+
+// UnknownServiceError is returned when a name matches none of the known services
+type UnknownServiceError struct {
+	Name string
+	// Suggestions are the closest known services, best first
+	Suggestions []string
+}
+
+func (e *UnknownServiceError) Error() string {
+	msg := fmt.Sprintf("'%s' is not a recognized service on frain", e.Name)
+	if len(e.Suggestions) > 0 {
+		msg += fmt.Sprintf(", did you mean %s?", strings.Join(e.Suggestions, " or "))
+	}
+
+	return msg
+}
+
+// ResolveService maps a name typed by the user to one of the known services. Aliases
+// are looked up first, then the name is compared with the known services ignoring
+// case, spaces, hyphens and underscores so that both "google_cloud" and the prettified
+// "Google cloud" resolve to the same service. When known is empty the name is returned
+// as is, after alias expansion, and the caller is left to validate it.
+func ResolveService(name string, known []string, aliases map[string]string) (string, error) {
+	key := normalizeName(name)
+	for alias, service := range aliases {
+		if normalizeName(alias) == key {
+			name, key = service, normalizeName(service)
+			break
+		}
+	}
+
+	if len(known) == 0 {
+		return strings.ToLower(strings.TrimSpace(name)), nil
+	}
+
+	for _, k := range known {
+		if normalizeName(k) == key {
+			return k, nil
+		}
+	}
+
+	return "", &UnknownServiceError{Name: name, Suggestions: suggest(key, known)}
+}
+
+// normalizeName reduces a service name to the form used when comparing names
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_', '\t':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// suggest returns the known services closest to key by edit distance. Only services
+// within a third of the length of key are considered close enough to be suggested.
+func suggest(key string, known []string) []string {
+	max := len([]rune(key))/3 + 1
+	best := max + 1
+
+	var suggestions []string
+	for _, k := range known {
+		d := levenshtein(key, normalizeName(k))
+		switch {
+		case d > max:
+			continue
+		case d < best:
+			best, suggestions = d, []string{k}
+		case d == best:
+			suggestions = append(suggestions, k)
+		}
+	}
+	sort.Strings(suggestions)
+
+	return suggestions
+}
+
+// levenshtein returns the minimum number of single rune insertions, deletions and
+// substitutions needed to turn a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+
+	return a
+}
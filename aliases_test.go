@@ -0,0 +1,83 @@
+package frain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveService(t *testing.T) {
+	known := []string{"circleci", "datadog", "github", "google_cloud", "mailgun", "mailchimp"}
+	aliases := map[string]string{"gh": "github", "gc": "google_cloud"}
+
+	tests := []struct {
+		name        string
+		want        string
+		suggestions []string
+	}{
+		{name: "github", want: "github"},
+		{name: "GitHub", want: "github"},
+		{name: "gh", want: "github"},
+		{name: "GC", want: "google_cloud"},
+		{name: "google-cloud", want: "google_cloud"},
+		{name: title("google_cloud"), want: "google_cloud"},
+		{name: "circlci", suggestions: []string{"circleci"}},
+		{name: "mailgum", suggestions: []string{"mailgun"}},
+		{name: "mail", suggestions: nil},
+		{name: "statuspage", suggestions: nil},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveService(tt.name, known, aliases)
+		if tt.want != "" {
+			if err != nil || got != tt.want {
+				t.Errorf("%s: expected %v, got %v (%v)", tt.name, tt.want, got, err)
+			}
+			continue
+		}
+
+		e, ok := err.(*UnknownServiceError)
+		if !ok {
+			t.Errorf("%s: expected an UnknownServiceError, got %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(e.Suggestions, tt.suggestions) {
+			t.Errorf("%s: expected suggestions %v, got %v", tt.name, tt.suggestions, e.Suggestions)
+		}
+	}
+}
+
+func TestResolveServiceWithoutList(t *testing.T) {
+	got, err := ResolveService("DD", nil, DefaultAliases)
+	if err != nil || got != "datadog" {
+		t.Errorf("expected datadog, got %v (%v)", got, err)
+	}
+
+	got, err = ResolveService("Fastly", nil, DefaultAliases)
+	if err != nil || got != "fastly" {
+		t.Errorf("expected fastly, got %v (%v)", got, err)
+	}
+}
+
+func TestUnknownServiceError(t *testing.T) {
+	err := &UnknownServiceError{Name: "circlci", Suggestions: []string{"circleci"}}
+	want := "'circlci' is not a recognized service on frain, did you mean circleci?"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+func TestConfigAliases(t *testing.T) {
+	cfg := &Config{
+		Services: []ServiceConfig{{Name: "gh", OnChange: []string{"notify"}}},
+		Aliases:  map[string]string{"gh": "github", "ci": "circleci"},
+	}
+
+	aliases := cfg.ServiceAliases()
+	if aliases["ci"] != "circleci" || aliases["dd"] != "datadog" {
+		t.Errorf("expected built-in and configured aliases, got %v", aliases)
+	}
+
+	if hooks := cfg.Hooks("github"); len(hooks) != 1 || hooks[0].Command != "notify" {
+		t.Errorf("expected the hook of the aliased service, got %v", hooks)
+	}
+}
@@ -0,0 +1,306 @@
+package frain
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// cachetTimeLayout is how Cachet writes times, in the time zone of the server which is
+// taken to be UTC
+const cachetTimeLayout = "2006-01-02 15:04:05"
+
+// cachetPageSize is the number of components or incidents asked for per request
+const cachetPageSize = 100
+
+// cachetUpdatedIncidents is the number of incidents, the latest first, whose updates are
+// fetched, each taking a request of its own. Older ones keep their first message only.
+const cachetUpdatedIncidents = 20
+
+// CachetProvider reads a service from the API of a Cachet status page, given the URL of
+// the page. Cachet incidents have no impact, so it is taken from the status of the
+// component they affect, and scheduled incidents become maintenances. Incidents are
+// listed the latest first and no longer once they predate the start day.
+type CachetProvider struct {
+	URL string
+	// Client fetches the page, DefaultClient when nil
+	Client *Client
+}
+
+type cachetPage struct {
+	Data json.RawMessage `json:"data"`
+	Meta struct {
+		Pagination struct {
+			Links struct {
+				NextPage string `json:"next_page"`
+			} `json:"links"`
+		} `json:"pagination"`
+	} `json:"meta"`
+}
+
+type cachetComponent struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Status      int    `json:"status"`
+	Enabled     bool   `json:"enabled"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type cachetIncident struct {
+	ID          int    `json:"id"`
+	ComponentID int    `json:"component_id"`
+	Name        string `json:"name"`
+	Status      int    `json:"status"`
+	Message     string `json:"message"`
+	ScheduledAt string `json:"scheduled_at"`
+	OccurredAt  string `json:"occurred_at"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type cachetUpdate struct {
+	ID        int    `json:"id"`
+	Status    int    `json:"status"`
+	Message   string `json:"message"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// Cachet status codes
+var (
+	cachetComponentStatuses = map[int]ComponentStatus{
+		1: ComponentOperational,
+		2: ComponentDegradedPerformance,
+		3: ComponentPartialOutage,
+		4: ComponentMajorOutage,
+	}
+	cachetIncidentStatuses = map[int]IncidentStatus{
+		0: IncidentScheduled,
+		1: IncidentInvestigating,
+		2: IncidentIdentified,
+		3: IncidentMonitoring,
+		4: IncidentResolved,
+	}
+)
+
+// GetService implements the Provider interface
+func (p CachetProvider) GetService(name string, startTime, endTime time.Time) (*Service, error) {
+	s := &Service{
+		Name:          name,
+		Provider:      ProviderCachet,
+		StatusPageURL: p.URL,
+		IsActive:      true,
+	}
+
+	var components []cachetComponent
+	err := p.list("/api/v1/components", func(data json.RawMessage) (bool, error) {
+		var page []cachetComponent
+		err := json.Unmarshal(data, &page)
+		components = append(components, page...)
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := map[int]ComponentStatus{}
+	for _, c := range components {
+		if !c.Enabled {
+			continue
+		}
+		statuses[c.ID] = cachetComponentStatuses[c.Status]
+		s.Components = append(s.Components, Component{
+			ID:          strconv.Itoa(c.ID),
+			ComponentID: strconv.Itoa(c.ID),
+			Name:        c.Name,
+			Status:      statuses[c.ID].String(),
+			Description: c.Description,
+			CreatedAt:   cachetTime(c.CreatedAt),
+			UpdatedAt:   cachetTime(c.UpdatedAt),
+		})
+	}
+
+	start := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, startTime.Location())
+
+	var incidents []cachetIncident
+	err = p.list("/api/v1/incidents?sort=id&order=desc", func(data json.RawMessage) (bool, error) {
+		var page []cachetIncident
+		if err := json.Unmarshal(data, &page); err != nil {
+			return false, err
+		}
+		incidents = append(incidents, page...)
+
+		more := len(page) > 0
+		for _, ci := range page {
+			if ci.Status != 0 && cachetTime(firstOf(ci.OccurredAt, ci.CreatedAt)).Before(start) {
+				more = false
+			}
+		}
+		return more, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updated := 0
+	for _, ci := range incidents {
+		status := cachetIncidentStatuses[ci.Status]
+		if status == IncidentScheduled {
+			// scheduled incidents stay so once their time has passed
+			if m := ci.maintenance(p.URL); !m.ScheduledFor.Before(start) {
+				s.Maintenances = append(s.Maintenances, m)
+			}
+			continue
+		}
+
+		i := ci.incident(p.URL, statuses[ci.ComponentID])
+		if len(createdBetween([]Incident{i}, startTime, endTime)) == 0 {
+			continue
+		}
+		if updated >= cachetUpdatedIncidents {
+			s.Incidents = append(s.Incidents, i)
+			continue
+		}
+		updated++
+
+		var updates []cachetUpdate
+		err := p.list(fmt.Sprintf("/api/v1/incidents/%d/updates", ci.ID), func(data json.RawMessage) (bool, error) {
+			var page []cachetUpdate
+			err := json.Unmarshal(data, &page)
+			updates = append(updates, page...)
+			return true, err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(updates) > 0 && len(i.IncidentUpdates) > 0 {
+			// the status the incident was reported with is lost once it is updated
+			i.IncidentUpdates[0].Status = IncidentInvestigating.String()
+		}
+		for _, u := range updates {
+			i.IncidentUpdates = append(i.IncidentUpdates, IncidentUpdate{
+				ID:               strconv.Itoa(u.ID),
+				IncidentUpdateID: strconv.Itoa(u.ID),
+				IncidentID:       i.ID,
+				Status:           cachetIncidentStatuses[u.Status].String(),
+				Body:             u.Message,
+				CreatedAt:        cachetTime(u.CreatedAt),
+				UpdatedAt:        cachetTime(u.UpdatedAt),
+			})
+		}
+		sortUpdates(i.IncidentUpdates)
+
+		s.Incidents = append(s.Incidents, i)
+	}
+
+	s.Indicator = worstIndicator(s.Components, s.Incidents)
+
+	return s, nil
+}
+
+// list fetches the pages of a list from the API, passing the data of each one to decode
+// which tells whether to fetch the next one
+func (p CachetProvider) list(path string, decode func(data json.RawMessage) (bool, error)) error {
+	u, err := url.Parse(p.URL + path)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("per_page", strconv.Itoa(cachetPageSize))
+	u.RawQuery = q.Encode()
+
+	for next := u.String(); next != ""; {
+		var page cachetPage
+		if err := getJSON(p.Client, next, &page); err != nil {
+			return err
+		}
+		more, err := decode(page.Data)
+		if err != nil {
+			return errJSONDecode
+		}
+		next = ""
+		if more {
+			next = page.Meta.Pagination.Links.NextPage
+		}
+	}
+
+	return nil
+}
+
+// incident turns a Cachet incident into one of frain, its message being the oldest
+// update. component is the status of the component it affects.
+func (ci cachetIncident) incident(base string, component ComponentStatus) Incident {
+	status := cachetIncidentStatuses[ci.Status]
+	impact := componentImpacts[component]
+	if impact < ImpactMinor {
+		impact = ImpactMinor
+	}
+
+	i := Incident{
+		ID:         strconv.Itoa(ci.ID),
+		IncidentID: strconv.Itoa(ci.ID),
+		Name:       ci.Name,
+		Status:     status.String(),
+		Impact:     impact.String(),
+		Shortlink:  fmt.Sprintf("%s/incidents/%d", base, ci.ID),
+		IsActive:   !status.Resolved(),
+		CreatedAt:  cachetTime(firstOf(ci.OccurredAt, ci.CreatedAt)),
+		UpdatedAt:  cachetTime(ci.UpdatedAt),
+	}
+	if status.Resolved() {
+		i.ResolvedAt = i.UpdatedAt
+	}
+	if ci.Message != "" {
+		// Cachet numbers updates apart from incidents, so the message gets an ID of
+		// its own
+		i.IncidentUpdates = []IncidentUpdate{{
+			ID:         "incident-" + i.ID,
+			IncidentID: i.ID,
+			Status:     i.Status,
+			Body:       ci.Message,
+			CreatedAt:  i.CreatedAt,
+			UpdatedAt:  i.CreatedAt,
+		}}
+	}
+
+	return i
+}
+
+func (ci cachetIncident) maintenance(base string) Maintenance {
+	m := Maintenance{
+		ID:           strconv.Itoa(ci.ID),
+		Name:         ci.Name,
+		Status:       IncidentScheduled.String(),
+		Impact:       ImpactMaintenance.String(),
+		Shortlink:    fmt.Sprintf("%s/incidents/%d", base, ci.ID),
+		ScheduledFor: cachetTime(ci.ScheduledAt),
+		CreatedAt:    cachetTime(ci.CreatedAt),
+		UpdatedAt:    cachetTime(ci.UpdatedAt),
+	}
+	if ci.Message != "" {
+		m.IncidentUpdates = []IncidentUpdate{{
+			ID:         "incident-" + m.ID,
+			IncidentID: m.ID,
+			Status:     m.Status,
+			Body:       ci.Message,
+			CreatedAt:  m.CreatedAt,
+			UpdatedAt:  m.CreatedAt,
+		}}
+	}
+
+	return m
+}
+
+// cachetTime reads a time written by Cachet, the zero time when it is missing
+func cachetTime(s string) time.Time {
+	t, err := time.Parse(cachetTimeLayout, s)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
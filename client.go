@@ -0,0 +1,177 @@
+package frain
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// DefaultHost is the frain backend queried when neither Client.Host nor the FRAIN_HOST
+// environment variable is set
+const DefaultHost = "https://frain-server.herokuapp.com/graphql"
+
+// RetryPolicy decides how often and how long apart failed requests are retried. Only
+// failures that are safe to repeat are retried: connection errors and timeouts, and the
+// 429, 502, 503 and 504 status codes.
+type RetryPolicy struct {
+	// Retries is the number of times a failed request is repeated
+	Retries int
+	// BaseDelay is the wait before the first retry. It doubles with every retry up to
+	// MaxDelay and is jittered so that clients do not retry in lockstep. A server asking
+	// for a longer wait with Retry-After is given up on rather than waited for.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// DefaultRetryPolicy gives the backend about ten seconds to come back, which covers
+// most of its cold starts
+var DefaultRetryPolicy = RetryPolicy{
+	Retries:   3,
+	BaseDelay: time.Second,
+	MaxDelay:  8 * time.Second,
+}
+
+// Client queries the frain backend
+type Client struct {
+	// Host is the GraphQL endpoint, FRAIN_HOST or DefaultHost when empty
+	Host       string
+	HTTPClient *http.Client
+	Retry      RetryPolicy
+
+	// sleep waits between attempts, replaced in tests
+	sleep func(time.Duration)
+}
+
+// DefaultClient is the client used by GetService and GetServiceList
+var DefaultClient = &Client{
+	HTTPClient: &http.Client{Timeout: 30 * time.Second},
+	Retry:      DefaultRetryPolicy,
+}
+
+func (c *Client) host() string {
+	if c.Host != "" {
+		return c.Host
+	}
+	if host := os.Getenv("FRAIN_HOST"); host != "" {
+		return host
+	}
+
+	return DefaultHost
+}
+
+// post sends the query to the backend, retrying according to the retry policy. The
+// caller must close the body of the returned response.
+func (c *Client) post(query []byte) (*http.Response, error) {
+	return c.send(http.MethodPost, c.host(), query)
+}
+
+// get fetches url, e.g. the status feed of a provider, retrying according to the retry
+// policy. The caller must close the body of the returned response.
+func (c *Client) get(url string) (*http.Response, error) {
+	return c.send(http.MethodGet, url, nil)
+}
+
+func (c *Client) send(method, url string, body []byte) (*http.Response, error) {
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	sleep := c.sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, url, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := hc.Do(req)
+		if err == nil && resp.StatusCode < 400 {
+			return resp, nil
+		}
+
+		if err == nil {
+			if !retryable(resp.StatusCode) || attempt >= c.Retry.Retries {
+				resp.Body.Close()
+				return nil, fmt.Errorf("Error: the server responded with %s", resp.Status)
+			}
+		} else if attempt >= c.Retry.Retries {
+			return nil, errHTTPPost
+		}
+
+		delay := c.Retry.backoff(attempt)
+		if err == nil {
+			// drain the body so that the connection can be reused
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			if d, ok := retryAfter(resp); ok {
+				if c.Retry.MaxDelay > 0 && d > c.Retry.MaxDelay {
+					return nil, fmt.Errorf("Error: the server responded with %s and asked to retry in %v", resp.Status, d.Round(time.Second))
+				}
+				delay = d
+			}
+		}
+		sleep(delay)
+	}
+}
+
+// backoff returns the jittered wait before the retry following the given attempt,
+// somewhere between half and all of the exponential delay
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseDelay
+	for i := 0; i < attempt && (p.MaxDelay <= 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func retryable(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// retryAfter reads the Retry-After header of a response, given either in seconds or as
+// an HTTP date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+
+	return 0, false
+}
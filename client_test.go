@@ -0,0 +1,209 @@
+package frain
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer fails the first failures requests with the given status code before
+// answering with a service list
+func flakyServer(failures int, code int, header http.Header) (*httptest.Server, *int32) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(atomic.AddInt32(&requests, 1)) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(code)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"getAllServices": [{"name": "github"}]}}`)
+	}))
+
+	return srv, &requests
+}
+
+func testClient(host string, retries int) (*Client, *[]time.Duration) {
+	var delays []time.Duration
+	return &Client{
+		Host:  host,
+		Retry: RetryPolicy{Retries: retries, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second},
+		sleep: func(d time.Duration) { delays = append(delays, d) },
+	}, &delays
+}
+
+func TestClientRetries(t *testing.T) {
+	for _, code := range []int{429, 502, 503, 504} {
+		srv, requests := flakyServer(2, code, nil)
+		c, delays := testClient(srv.URL, 3)
+
+		sl, err := c.GetServiceList()
+		srv.Close()
+		if err != nil {
+			t.Errorf("%d: expected success after retrying, got %v", code, err)
+			continue
+		}
+		if len(sl) != 1 || sl[0] != "github" {
+			t.Errorf("%d: unexpected service list %v", code, sl)
+		}
+		if *requests != 3 || len(*delays) != 2 {
+			t.Errorf("%d: expected 3 requests and 2 waits, got %d and %d", code, *requests, len(*delays))
+		}
+	}
+}
+
+func TestClientGivesUp(t *testing.T) {
+	srv, requests := flakyServer(10, http.StatusServiceUnavailable, nil)
+	defer srv.Close()
+	c, _ := testClient(srv.URL, 2)
+
+	if _, err := c.GetServiceList(); err == nil {
+		t.Error("expected an error once the retries are exhausted")
+	}
+	if *requests != 3 {
+		t.Errorf("expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestClientDoesNotRetry(t *testing.T) {
+	for _, code := range []int{400, 404, 500} {
+		srv, requests := flakyServer(1, code, nil)
+		c, _ := testClient(srv.URL, 3)
+
+		_, err := c.GetServiceList()
+		srv.Close()
+		if err == nil {
+			t.Errorf("%d: expected an error", code)
+		}
+		if *requests != 1 {
+			t.Errorf("%d: expected a single request, got %d", code, *requests)
+		}
+	}
+}
+
+func TestClientRetryAfter(t *testing.T) {
+	srv, _ := flakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	defer srv.Close()
+	c, delays := testClient(srv.URL, 3)
+
+	if _, err := c.GetServiceList(); err != nil {
+		t.Fatal(err)
+	}
+	if len(*delays) != 1 || (*delays)[0] != time.Second {
+		t.Errorf("expected to wait 1s as asked by the server, waited %v", *delays)
+	}
+}
+
+func TestClientRetryAfterTooLong(t *testing.T) {
+	for _, after := range []string{"3600", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)} {
+		srv, requests := flakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": {after}})
+		c, delays := testClient(srv.URL, 3)
+
+		_, err := c.GetServiceList()
+		srv.Close()
+		if err == nil || !strings.Contains(err.Error(), "retry in") {
+			t.Errorf("%s: expected to give up, got %v", after, err)
+		}
+		if *requests != 1 || len(*delays) != 0 {
+			t.Errorf("%s: expected a single request and no wait, got %d and %v", after, *requests, *delays)
+		}
+	}
+}
+
+func TestClientConnectionError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	host := srv.URL
+	srv.Close()
+
+	c, delays := testClient(host, 2)
+	if _, err := c.GetServiceList(); err != errHTTPPost {
+		t.Errorf("expected %v, got %v", errHTTPPost, err)
+	}
+	if len(*delays) != 2 {
+		t.Errorf("expected 2 retries, got %d", len(*delays))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if d := p.backoff(attempt); d < max/2 || d > max {
+				t.Errorf("attempt %d: expected a delay between %v and %v, got %v", attempt, max/2, max, d)
+			}
+		}
+	}
+}
+
+func TestMaintenancesAskedSeparately(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "scheduledMaintenances") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors": [{"message": "Cannot query field \"scheduledMaintenances\""}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"getService": {"name": "github", "indicator": "none"}}}`)
+	}))
+	defer srv.Close()
+
+	c, _ := testClient(srv.URL, 0)
+	s, err := c.GetService("github", time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("expected the service without maintenances, got %v", err)
+	}
+	if s.Name != "github" || s.Maintenances != nil {
+		t.Errorf("unexpected service %+v", s)
+	}
+}
+
+func TestMaintenancesAskedConcurrently(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(200 * time.Millisecond)
+		body, _ := ioutil.ReadAll(r.Body)
+		if strings.Contains(string(body), "scheduledMaintenances") {
+			// the maintenances are not retried
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"data": {"getService": {"name": "github", "indicator": "none"}}}`)
+	}))
+	defer srv.Close()
+
+	c, _ := testClient(srv.URL, 3)
+	start := time.Now()
+	if _, err := c.GetService("github", time.Now(), time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed >= 400*time.Millisecond {
+		t.Errorf("expected both queries at once, took %v", elapsed)
+	}
+	if n := atomic.LoadInt32(&requests); n != 2 {
+		t.Errorf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data": null, "errors": [{"message": "service lookup timed out"}]}`)
+	}))
+	defer srv.Close()
+
+	c, _ := testClient(srv.URL, 0)
+	if _, err := c.GetService("github", time.Now(), time.Now()); err == nil || !strings.Contains(err.Error(), "service lookup timed out") {
+		t.Errorf("expected the backend error, got %v", err)
+	}
+	if _, err := c.GetServiceList(); err == nil {
+		t.Error("expected the backend error for the service list")
+	}
+}
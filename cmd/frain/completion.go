@@ -0,0 +1,267 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+// serviceListTTL is how long the cached list of supported services is used for
+// completion before it is fetched again
+const serviceListTTL = 24 * time.Hour
+
+// completionClient fetches the service list while completing, briefly and without
+// retrying so that pressing tab never hangs on a slow backend
+var completionClient = &frain.Client{HTTPClient: &http.Client{Timeout: 2 * time.Second}}
+
+// serviceCommands lists the commands whose arguments are service names along with
+// whether they take more than one
+var serviceCommands = map[string]bool{
+	"status":      false,
+	"incidents":   false,
+	"components":  false,
+	"maintenance": false,
+	"diff":        false,
+	"digest":      true,
+	"watch":       true,
+	"tui":         true,
+	"statusline":  true,
+	"correlate":   true,
+}
+
+func init() {
+	register(&command{
+		name:     "completion",
+		args:     "bash|zsh|fish",
+		summary:  "Generates a shell completion script",
+		examples: []string{"source <(frain completion bash)\t==> Enable tab completion in bash"},
+		run:      runCompletion,
+	})
+	register(&command{
+		name:    "__complete",
+		hidden:  true,
+		rawArgs: true,
+		run:     runComplete,
+	})
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for frain
+# Load it with: source <(frain completion bash)
+_frain_completions() {
+    local IFS=$'\n'
+    COMPREPLY=($(frain __complete "${COMP_WORDS[@]:1:$COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _frain_completions frain
+`,
+	"zsh": `#compdef frain
+# zsh completion for frain
+# Load it with: source <(frain completion zsh)
+_frain() {
+    local -a candidates
+    candidates=("${(@f)$(frain __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    compadd -a candidates
+}
+compdef _frain frain
+`,
+	"fish": `# fish completion for frain
+# Load it with: frain completion fish | source
+complete -c frain -f -a '(frain __complete (commandline -opc)[2..-1] (commandline -ct))'
+`,
+}
+
+func runCompletion(args []string) {
+	if len(args) != 1 {
+		fmt.Println("frain: completion needs a shell i.e. bash, zsh or fish")
+		exit()
+	}
+
+	script, ok := completionScripts[args[0]]
+	if !ok {
+		fmt.Printf("frain: unsupported shell '%s' for completion i.e. bash, zsh or fish\n", args[0])
+		exit()
+	}
+
+	fmt.Print(script)
+}
+
+// runComplete prints the completion candidates for the last of the given words, the
+// words preceding it being the arguments already typed after "frain"
+func runComplete(args []string) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+
+	for _, c := range complete(args[:len(args)-1], args[len(args)-1]) {
+		fmt.Println(c)
+	}
+}
+
+func complete(words []string, current string) []string {
+	// collect positional arguments, skipping flags and the values they take
+	fs := flag.CommandLine
+	var positional []string
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !strings.HasPrefix(w, "-") {
+			positional = append(positional, w)
+			if len(positional) == 1 {
+				fs = statusFlags
+				if cmd, ok := commands[w]; ok {
+					fs = cmd.flags
+				}
+			}
+			continue
+		}
+
+		name := strings.TrimLeft(w, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) {
+			i++
+		}
+	}
+
+	if strings.HasPrefix(current, "-") {
+		if len(positional) == 0 {
+			// global options or those of the status shorthand
+			return withPrefix(append(flagNames(flag.CommandLine), flagNames(statusFlags)...), current)
+		}
+		return withPrefix(flagNames(fs), current)
+	}
+
+	switch {
+	case len(positional) == 0:
+		var candidates []string
+		for _, cmd := range sortedCommands() {
+			candidates = append(candidates, cmd.name)
+		}
+		candidates = append(candidates, completionServices()...)
+		return withPrefix(candidates, current)
+
+	case positional[0] == "completion":
+		if len(positional) == 1 {
+			return withPrefix([]string{"bash", "fish", "zsh"}, current)
+		}
+
+	case positional[0] == "help":
+		if len(positional) == 1 {
+			var candidates []string
+			for _, cmd := range sortedCommands() {
+				candidates = append(candidates, cmd.name)
+			}
+			return withPrefix(candidates, current)
+		}
+
+	case len(positional) == 1 && isServiceCommand(positional[0]), serviceCommands[positional[0]]:
+		return withPrefix(completionServices(), current)
+
+	case isServiceCommand(positional[0]):
+		// arguments following the service e.g. incident dates
+		return nil
+
+	case len(positional) == 1:
+		// frain <service> incidents|maintenance
+		return withPrefix([]string{"incidents", "maintenance"}, current)
+	}
+
+	return nil
+}
+
+func isServiceCommand(name string) bool {
+	_, ok := serviceCommands[name]
+	return ok
+}
+
+func flagNames(fs *flag.FlagSet) []string {
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) {
+		if len(f.Name) == 1 {
+			names = append(names, "-"+f.Name)
+		} else {
+			names = append(names, "--"+f.Name)
+		}
+	})
+
+	return names
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			matches = append(matches, c)
+		}
+	}
+
+	return matches
+}
+
+// completionServices returns the configured services along with the supported ones,
+// the latter being cached on disk for a day
+func completionServices() []string {
+	seen := map[string]bool{}
+	var services []string
+	for _, s := range append(loadConfig().ServiceNames(), cachedServiceList(completionClient)...) {
+		if !seen[s] {
+			seen[s] = true
+			services = append(services, s)
+		}
+	}
+	sort.Strings(services)
+
+	return services
+}
+
+// cachedServiceList returns the supported services, fetching them with c unless the
+// cached list is less than a day old
+func cachedServiceList(c *frain.Client) []string {
+	path := serviceListPath()
+
+	var cached []string
+	if data, err := ioutil.ReadFile(path); err == nil {
+		json.Unmarshal(data, &cached)
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < serviceListTTL {
+			return cached
+		}
+	}
+
+	sl, err := c.GetServiceList()
+	if err != nil {
+		// a stale list is better than none
+		return cached
+	}
+
+	if path != "" {
+		if data, err := json.Marshal(sl); err == nil {
+			os.MkdirAll(filepath.Dir(path), 0755)
+			ioutil.WriteFile(path, data, 0644)
+		}
+	}
+
+	return sl
+}
+
+func serviceListPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "frain", "services.json")
+}
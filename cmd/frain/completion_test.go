@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestComplete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frain-complete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// a fresh cached service list and a configuration file, so that nothing is fetched
+	for _, env := range []string{"HOME", "XDG_CACHE_HOME", "XDG_CONFIG_HOME"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Setenv(env, dir)
+	}
+	os.MkdirAll(filepath.Dir(serviceListPath()), 0755)
+	if err := ioutil.WriteFile(serviceListPath(), []byte(`["circleci","fastly","github"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "config.json")
+	if err := ioutil.WriteFile(config, []byte(`{"services": [{"name": "acme", "provider": "cachet", "url": "https://status.acme.test"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path string) { *configFlag = path }(*configFlag)
+	*configFlag = config
+
+	tests := []struct {
+		words   []string
+		current string
+		want    []string
+	}{
+		{nil, "ci", []string{"circleci"}},
+		{nil, "a", []string{"acme"}},
+		{nil, "--ret", []string{"--retries"}},
+		{[]string{"completion"}, "", []string{"bash", "fish", "zsh"}},
+		{[]string{"help"}, "wa", []string{"watch"}},
+		{[]string{"status"}, "f", []string{"fastly"}},
+		{[]string{"status", "github"}, "", nil},
+		{[]string{"watch", "github"}, "c", []string{"circleci"}},
+		{[]string{"watch", "--interval", "1m"}, "g", []string{"github"}},
+		{[]string{"watch"}, "--on", []string{"--on-change"}},
+		{[]string{"github"}, "i", []string{"incidents"}},
+		{[]string{"--color", "never"}, "fa", []string{"fake-server", "fastly"}},
+	}
+	for _, tt := range tests {
+		if got := complete(tt.words, tt.current); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q %q: expected %q, got %q", tt.words, tt.current, tt.want, got)
+		}
+	}
+}
+
+func TestStaleServiceList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frain-complete")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, env := range []string{"HOME", "XDG_CACHE_HOME"} {
+		defer os.Setenv(env, os.Getenv(env))
+		os.Setenv(env, dir)
+	}
+	os.MkdirAll(filepath.Dir(serviceListPath()), 0755)
+	if err := ioutil.WriteFile(serviceListPath(), []byte(`["github"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * serviceListTTL)
+	os.Chtimes(serviceListPath(), old, old)
+
+	// the backend is gone, so the stale list is kept after a single attempt
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	c := *completionClient
+	c.Host = srv.URL
+	start := time.Now()
+	if got := cachedServiceList(&c); !reflect.DeepEqual(got, []string{"github"}) {
+		t.Errorf("expected the stale list, got %q", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected completion not to wait for the backend, took %v", elapsed)
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// sampleConfig is written by "frain config init"
+const sampleConfig = `{
+  "services": [
+    {"name": "github"},
+    {"name": "circleci"}
+  ]
+}
+`
+
+func init() {
+	register(&command{
+		name:     "config",
+		args:     "[show|path|init]",
+		summary:  "Shows or creates the configuration file",
+		examples: []string{"frain config init\t==> Create a configuration file to edit"},
+		notes: "show\tprints the configuration in use (default)\n" +
+			"path\tprints the location of the configuration file\n" +
+			"init\twrites a sample configuration file if none exists",
+		run: runConfig,
+	})
+}
+
+func runConfig(args []string) {
+	action := "show"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	path := configPath()
+	switch action {
+	case "path":
+		fmt.Println(path)
+
+	case "show":
+		cfg := loadConfig()
+		if cfg.SMTP.Password != "" {
+			cfg.SMTP.Password = "********"
+		}
+
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		fmt.Printf("# %s\n%s\n", path, data)
+
+	case "init":
+		if _, err := os.Stat(path); err == nil {
+			fmt.Printf("frain: configuration file %s already exists\n", path)
+			exit()
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		if err := ioutil.WriteFile(path, []byte(sampleConfig), 0644); err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		fmt.Println("Configuration file written to", path)
+
+	default:
+		fmt.Printf("frain: unknown config action '%s' (\"frain help config\" for help)\n", action)
+		exit()
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	correlateFlags = flag.NewFlagSet("correlate", flag.ExitOnError)
+
+	correlateSince  = correlateFlags.Duration("since", 24*time.Hour, "Compare the snapshots recorded this far back")
+	correlateFormat = correlateFlags.String("format", "txt", "Output format i.e. txt or json")
+)
+
+func init() {
+	register(&command{
+		name:     "correlate",
+		args:     "[<service>...]",
+		summary:  "Finds where probes and status pages disagree",
+		examples: []string{"frain correlate --since 168h github\t==> Show when github hid problems over the last week"},
+		notes: "Only probes naming the vendor \"component\" they observe are compared. Services are\n" +
+			"fetched once more and compared over the snapshot cache, so running \"frain watch\"\n" +
+			"meanwhile tells how long the vendor took to acknowledge a problem. Snapshots are\n" +
+			"kept for 8 days.",
+		flags: correlateFlags,
+		run:   runCorrelate,
+	})
+}
+
+func runCorrelate(args []string) {
+	cfg := loadConfig()
+	names := serviceArgs(args, "correlate")
+	since := time.Now().Add(-*correlateSince)
+	if *correlateSince > frain.MaxSnapshotAge {
+		fmt.Fprintf(os.Stderr, "frain: snapshots are only kept for %s, older discrepancies are not found\n", frain.MaxSnapshotAge)
+	}
+
+	// an incident naming a component acknowledges a problem however old it is
+	services := fetchAvailable(names, historyStart, time.Now())
+	now := time.Now()
+
+	discrepancies := []frain.Discrepancy{}
+	for i, name := range names {
+		probes := cfg.Probes(name)
+		if len(probes) == 0 {
+			continue
+		}
+
+		var history []*frain.Snapshot
+		if dir := frain.DefaultSnapshotDir(); dir != "" {
+			history, _ = frain.SnapshotsSince(dir, name, since)
+		}
+		if len(history) == 0 && services[i] != nil {
+			history = []*frain.Snapshot{{TakenAt: now, Service: services[i]}}
+		}
+		discrepancies = append(discrepancies, frain.Correlate(name, probes, history)...)
+	}
+
+	var err error
+	switch strings.ToLower(*correlateFormat) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(discrepancies)
+	case "txt":
+		err = frain.WriteDiscrepancyText(os.Stdout, discrepancies)
+	default:
+		err = fmt.Errorf("bad format specified '%s'", *correlateFormat)
+	}
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
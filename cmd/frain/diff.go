@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	diffFlags = flag.NewFlagSet("diff", flag.ExitOnError)
+
+	diffSince  = diffFlags.Duration("since", 0, "Compare the live service with its snapshot from this long ago")
+	diffFormat = diffFlags.String("format", "txt", "Output format i.e. txt or json")
+)
+
+func init() {
+	register(&command{
+		name:     "diff",
+		args:     "<snapshot-a> <snapshot-b> | --since <duration> <service>",
+		summary:  "Shows what changed between two snapshots of a service",
+		examples: []string{"frain diff --since 1h github\t==> Show what changed on github in the last hour"},
+		notes: "Snapshots are files written with --save. Every fetch is also recorded in the\n" +
+			"snapshot cache used by --since.",
+		flags: diffFlags,
+		run:   runDiff,
+	})
+}
+
+func runDiff(args []string) {
+	var a, b *frain.Snapshot
+	var err error
+
+	switch {
+	case *diffSince > 0 && len(args) == 1:
+		name, err := resolveService(args[0])
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		a, err = frain.FindSnapshot(frain.DefaultSnapshotDir(), name, time.Now().Add(-*diffSince))
+		if err != nil {
+			fmt.Printf("frain: %v (run \"frain %s\" to record one)\n", err, name)
+			exit()
+		}
+
+		// incidents created before the snapshot may still change, so the whole history
+		// is fetched as status does, which also keeps the recorded snapshot complete
+		services, err := fetchServices([]string{name}, historyStart, time.Now())
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		b = &frain.Snapshot{TakenAt: time.Now(), Service: services[0]}
+
+	case *diffSince == 0 && len(args) == 2:
+		if a, err = frain.LoadSnapshot(args[0]); err == nil {
+			b, err = frain.LoadSnapshot(args[1])
+		}
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+
+	default:
+		fmt.Println("frain: diff needs either two snapshots or --since and a service (\"frain diff -h\" for help)")
+		exit()
+	}
+
+	d := frain.Diff(a, b)
+	switch strings.ToLower(*diffFormat) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(d)
+	case "txt":
+		err = d.WriteText(os.Stdout)
+	default:
+		err = fmt.Errorf("bad format specified '%s'", *diffFormat)
+	}
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
+
+// recordSnapshot keeps a copy of a service fetched at t with the incidents created from
+// startTime to endTime in the snapshot cache. Diffs and correlations take a snapshot for
+// the whole service, so one left without part of the incident history is not kept.
+// Failures are ignored as the cache only serves later diffs.
+func recordSnapshot(s *frain.Service, startTime, endTime, t time.Time) {
+	day := t.In(endTime.Location())
+	today := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	if startTime.After(historyStart) || endTime.Before(today) {
+		return
+	}
+	if dir := frain.DefaultSnapshotDir(); dir != "" {
+		frain.RecordSnapshot(dir, s, t)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+func TestPartialSnapshotsNotRecorded(t *testing.T) {
+	now := time.Now()
+	defer useBackend(t, &frain.Service{
+		Name:      "acme",
+		Incidents: []frain.Incident{{ID: "1", Name: "Old outage", Status: "resolved", CreatedAt: now.AddDate(0, 0, -5)}},
+	})()
+
+	windows := []struct {
+		start, end time.Time
+		recorded   bool
+	}{
+		{now.AddDate(0, 0, -1), now, false},
+		{historyStart, now.AddDate(0, 0, -2), false},
+		{historyStart, now, true},
+	}
+	for _, w := range windows {
+		if _, err := fetchServices([]string{"acme"}, w.start, w.end); err != nil {
+			t.Fatal(err)
+		}
+		_, err := frain.LatestSnapshot(frain.DefaultSnapshotDir(), "acme")
+		if recorded := err == nil; recorded != w.recorded {
+			t.Errorf("%v to %v: expected recorded to be %v, got %v", w.start, w.end, w.recorded, recorded)
+		}
+	}
+}
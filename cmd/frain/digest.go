@@ -0,0 +1,150 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	digestFlags = flag.NewFlagSet("digest", flag.ExitOnError)
+
+	digestSince = digestFlags.Duration("since", 24*time.Hour, "Reporting window ending now")
+	digestSMTP  = digestFlags.String("smtp", "", "Address (host:port) of the SMTP server to send the digest through")
+	digestFrom  = digestFlags.String("from", "", "Sender address of the digest email")
+	digestTo    = digestFlags.String("to", "", "Comma separated list of recipients")
+	digestUser  = digestFlags.String("user", "", "Username for SMTP authentication")
+)
+
+func init() {
+	register(&command{
+		name:     "digest",
+		args:     "[<service>...]",
+		summary:  "Summarises recent incidents, optionally sending them by email",
+		examples: []string{"frain digest --since 24h --smtp localhost:25\t==> Email a digest of configured services"},
+		notes: "Services default to those listed in the configuration file. Without --smtp the\n" +
+			"digest is printed instead of being sent. The SMTP password is read from FRAIN_SMTP_PASSWORD.",
+		flags: digestFlags,
+		run:   runDigest,
+	})
+}
+
+func runDigest(args []string) {
+	cfg := loadConfig()
+	smtpCfg := cfg.SMTP
+	if *digestSMTP != "" {
+		smtpCfg.Addr = *digestSMTP
+	}
+	if *digestFrom != "" {
+		smtpCfg.From = *digestFrom
+	}
+	if *digestTo != "" {
+		smtpCfg.To = strings.Split(*digestTo, ",")
+	}
+	if *digestUser != "" {
+		smtpCfg.Username = *digestUser
+	}
+	if p := os.Getenv("FRAIN_SMTP_PASSWORD"); p != "" {
+		smtpCfg.Password = p
+	}
+
+	names := args
+	if len(names) == 0 {
+		names = cfg.ServiceNames()
+	}
+	if len(names) == 0 {
+		fmt.Println("frain: no service specified for digest (\"frain digest -h\" for help)")
+		exit()
+	}
+
+	d, err := buildDigest(names, *digestSince, time.Now())
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+	if smtpCfg.Addr == "" {
+		d.WriteText(os.Stdout)
+		return
+	}
+
+	var auth smtp.Auth
+	if smtpCfg.Username != "" {
+		host, _, _ := net.SplitHostPort(smtpCfg.Addr)
+		auth = smtp.PlainAuth("", smtpCfg.Username, smtpCfg.Password, host)
+	}
+
+	if err := frain.SendDigest(smtpCfg.Addr, auth, smtpCfg.From, smtpCfg.To, d); err != nil {
+		fmt.Println("frain: failed to send digest:", err)
+		exit()
+	}
+	fmt.Printf("Digest sent to %s\n", strings.Join(smtpCfg.To, ", "))
+}
+
+// buildDigest fetches the services and digests their incidents over the window of the
+// given length ending at until. The whole incident history is fetched, as an incident
+// created before the window may be ongoing or get resolved within it.
+func buildDigest(names []string, since time.Duration, until time.Time) (*frain.Digest, error) {
+	services, err := fetchServices(names, historyStart, until)
+	if err != nil {
+		return nil, err
+	}
+
+	return frain.NewDigest(services, until.Add(-since), until), nil
+}
+
+func fetchServices(names []string, startTime, endTime time.Time) ([]*frain.Service, error) {
+	var c = make(chan int)
+	go progress(c)
+	defer func() {
+		c <- 1
+		clear()
+	}()
+
+	var services []*frain.Service
+	for _, name := range names {
+		name, err := resolveService(name)
+		if err != nil {
+			return nil, err
+		}
+		service, err := getService(name, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		if strings.ToLower(service.Name) != name {
+			return nil, fmt.Errorf("'%s' is not a recognized service on frain (see \"frain list\")", name)
+		}
+		recordSnapshot(service, startTime, endTime, time.Now())
+		services = append(services, service)
+	}
+
+	return services, nil
+}
+
+// fetchAvailable fetches the services it can, leaving nil in place of a service that
+// cannot be fetched so that one unavailable service does not fail a whole check
+func fetchAvailable(names []string, startTime, endTime time.Time) []*frain.Service {
+	var c = make(chan int)
+	go progress(c)
+	defer func() {
+		c <- 1
+		clear()
+	}()
+
+	services := make([]*frain.Service, len(names))
+	for i, name := range names {
+		service, err := getService(name, startTime, endTime)
+		if err != nil || strings.ToLower(service.Name) != name {
+			continue
+		}
+		recordSnapshot(service, startTime, endTime, time.Now())
+		services[i] = service
+	}
+
+	return services
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mekilis/frain"
+	"github.com/mekilis/frain/frainstest"
+)
+
+// useBackend points frain at a fake backend serving services, with the cache and the
+// configuration in a temporary directory, and returns a function undoing it all
+func useBackend(t *testing.T, services ...*frain.Service) func() {
+	dir, err := ioutil.TempDir("", "frain-cmd")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := frainstest.NewServer(services...)
+
+	env := map[string]string{"HOME": dir, "XDG_CACHE_HOME": dir, "XDG_CONFIG_HOME": dir, "FRAIN_HOST": srv.URL}
+	saved := map[string]string{}
+	for k, v := range env {
+		saved[k] = os.Getenv(k)
+		os.Setenv(k, v)
+	}
+
+	return func() {
+		for k, v := range saved {
+			os.Setenv(k, v)
+		}
+		srv.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	now := time.Now().UTC()
+	defer useBackend(t, &frain.Service{
+		Name: "acme",
+		Incidents: []frain.Incident{
+			{ID: "1", Name: "Database down", Impact: "major", Status: "identified", CreatedAt: now.AddDate(0, 0, -3)},
+			{ID: "2", Name: "Slow API", Impact: "minor", Status: "resolved", CreatedAt: now.AddDate(0, 0, -2), ResolvedAt: now.Add(-4 * time.Hour)},
+			{ID: "3", Name: "Login errors", Impact: "minor", Status: "investigating", CreatedAt: now.Add(-2 * time.Hour)},
+			{ID: "4", Name: "Old outage", Impact: "critical", Status: "resolved", CreatedAt: now.AddDate(0, 0, -9), ResolvedAt: now.AddDate(0, 0, -8)},
+		},
+	})()
+
+	d, err := buildDigest([]string{"acme"}, 24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{"Database down": frain.DigestOngoing, "Slow API": frain.DigestResolved, "Login errors": frain.DigestNew}
+	got := map[string]string{}
+	for _, g := range d.Groups {
+		for _, e := range g.Entries {
+			got[e.Incident.Name] = e.State
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	for name, state := range want {
+		if got[name] != state {
+			t.Errorf("%s: expected %s, got %q", name, state, got[name])
+		}
+	}
+}
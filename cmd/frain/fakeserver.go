@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+
+	"github.com/mekilis/frain"
+	"github.com/mekilis/frain/frainstest"
+)
+
+var (
+	fakeServerFlags = flag.NewFlagSet("fake-server", flag.ExitOnError)
+
+	fakeServerAddr       = fakeServerFlags.String("addr", "127.0.0.1:8080", "Address to listen on")
+	fakeServerFixtures   = fakeServerFlags.String("fixtures", "", "Directory of JSON fixture files, one per service")
+	fakeServerFail       = fakeServerFlags.Int("fail", 0, "Number of requests to fail before answering")
+	fakeServerFailStatus = fakeServerFlags.Int("fail-status", http.StatusServiceUnavailable, "HTTP status code of the failed requests")
+	fakeServerMalformed  = fakeServerFlags.Int("malformed", 0, "Number of requests answered with malformed JSON after the failures")
+	fakeServerDelay      = fakeServerFlags.Duration("delay", 0, "Time to hold back every response")
+)
+
+func init() {
+	register(&command{
+		name:    "fake-server",
+		summary: "Runs a fake frain backend serving fixture files",
+		examples: []string{
+			"frain fake-server --fixtures frainstest/fixtures\t==> Serve the sample fixtures on 127.0.0.1:8080",
+		},
+		notes: "Point frain at the fake backend with FRAIN_HOST, e.g.\n" +
+			"\tFRAIN_HOST=http://127.0.0.1:8080 frain github\n" +
+			"The incidents of the fixtures are also published as a feed on /feed.xml.",
+		flags: fakeServerFlags,
+		run:   runFakeServer,
+	})
+}
+
+func runFakeServer(args []string) {
+	if *fakeServerFixtures == "" {
+		fmt.Println("frain: fake-server needs a fixture directory (\"frain fake-server -h\" for help)")
+		exit()
+	}
+
+	services, err := frainstest.LoadFixtures(*fakeServerFixtures)
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+
+	b := frainstest.NewBackend(services...)
+	b.SetDelay(*fakeServerDelay)
+	for i := 0; i < *fakeServerFail; i++ {
+		b.Script(frainstest.Fault{Status: *fakeServerFailStatus})
+	}
+	for i := 0; i < *fakeServerMalformed; i++ {
+		b.Script(frainstest.Fault{Malformed: true})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", b)
+	mux.Handle("/feed.xml", frain.FeedHandler(func() []*frain.Service { return services }, frain.FeedOptions{}))
+
+	fmt.Printf("Serving %d service(s) on http://%s\n", len(services), *fakeServerAddr)
+	if err := http.ListenAndServe(*fakeServerAddr, mux); err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	feedFlags = flag.NewFlagSet("feed", flag.ExitOnError)
+
+	feedFormat = feedFlags.String("format", frain.FeedAtom, "Feed format i.e. atom or rss")
+	feedSince  = feedFlags.Duration("since", 7*24*time.Hour, "Publish the incident updates posted this long ago and since")
+)
+
+func init() {
+	register(&command{
+		name:     "feed",
+		args:     "[<service>...]",
+		summary:  "Writes the incidents of services as one Atom or RSS feed",
+		examples: []string{"frain feed --format rss > vendors.xml\t==> Export an RSS feed of the configured services"},
+		notes: "Services default to those listed in the configuration file. Every incident update\n" +
+			"is an entry with a GUID built from the incident and update IDs, so that feed readers\n" +
+			"show it once. \"frain serve\" publishes the same feed over HTTP.",
+		flags: feedFlags,
+		run:   runFeed,
+	})
+}
+
+func runFeed(args []string) {
+	names := serviceArgs(args, "feed")
+	// updates may be posted long after their incident was created
+	services := fetchAvailable(names, historyStart, time.Now())
+
+	if err := frain.WriteFeed(os.Stdout, *feedFormat, services, frain.FeedOptions{MaxAge: *feedSince}); err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
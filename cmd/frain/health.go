@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	healthFlags = flag.NewFlagSet("health", flag.ExitOnError)
+
+	healthFormat  = healthFlags.String("format", "txt", "Output format i.e. txt or json")
+	healthReasons = healthFlags.Int("reasons", 5, "Number of reasons dragging the score down to print, 0 for all")
+	healthIgnore  = healthFlags.Bool("ignore-maintenance", false, "Leave planned maintenance out of the score")
+)
+
+// exit codes of the health command, following the convention of monitoring plugins
+var healthExitCodes = map[frain.HealthLevel]int{
+	frain.HealthOK:       0,
+	frain.HealthWarning:  1,
+	frain.HealthCritical: 2,
+	frain.HealthUnknown:  3,
+}
+
+func init() {
+	register(&command{
+		name:     "health",
+		args:     "[<service>...]",
+		summary:  "Combines services into one health level and score",
+		examples: []string{"frain health\t==> Tell whether the configured services are OK"},
+		notes: "Services default to those listed in the configuration file, where a service may\n" +
+			"set a \"weight\" in the score and list its \"criticalComponents\". The exit status\n" +
+			"is 0 when healthy, 1 on warning, 2 when critical and 3 when unknown. Planned\n" +
+			"maintenance is left out with --ignore-maintenance.",
+		flags: healthFlags,
+		run:   runHealth,
+	})
+}
+
+func runHealth(args []string) {
+	cfg := loadConfig()
+	names := args
+	if len(names) == 0 {
+		names = cfg.ServiceNames()
+	}
+	if len(names) == 0 {
+		fmt.Println("frain: no service specified for health (\"frain health -h\" for help)")
+		exit()
+	}
+
+	for i, name := range names {
+		resolved, err := resolveService(name)
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		names[i] = resolved
+	}
+
+	// an incident opened long ago may still be unresolved
+	services := fetchAvailable(names, historyStart, time.Now())
+	if *healthIgnore {
+		for i, s := range services {
+			services[i] = frain.WithoutMaintenance(s, time.Now())
+		}
+	}
+	h := frain.AssessHealth(names, services, cfg.HealthConfig())
+
+	var err error
+	switch strings.ToLower(*healthFormat) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(h)
+	case "txt":
+		err = h.WriteText(os.Stdout, *healthReasons)
+	default:
+		err = fmt.Errorf("bad format specified '%s'", *healthFormat)
+	}
+	if err != nil {
+		fmt.Println("frain:", err)
+		os.Exit(healthExitCodes[frain.HealthUnknown])
+	}
+
+	os.Exit(healthExitCodes[h.Level])
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	impactFlags = flag.NewFlagSet("impact", flag.ExitOnError)
+
+	impactAll    = impactFlags.Bool("all", false, "Also list the systems that are not affected")
+	impactFormat = impactFlags.String("format", "txt", "Output format i.e. txt or json")
+	impactIgnore = impactFlags.Bool("ignore-maintenance", false, "Do not count planned maintenance as an impact")
+)
+
+func init() {
+	register(&command{
+		name:     "impact",
+		args:     "[<system>...]",
+		summary:  "Lists which of our systems are affected by vendor problems",
+		examples: []string{"frain impact\t==> Show what the current vendor problems break for us"},
+		notes: "Systems and the vendor services or components they depend on are listed under\n" +
+			"\"systems\" in the configuration file. All systems are checked unless some are named.",
+		flags: impactFlags,
+		run:   runImpact,
+	})
+}
+
+func runImpact(args []string) {
+	cfg := loadConfig()
+	systems := cfg.Systems
+	if len(args) > 0 {
+		systems = nil
+		for _, name := range args {
+			found := false
+			for _, sys := range cfg.Systems {
+				if strings.EqualFold(sys.Name, name) {
+					systems = append(systems, sys)
+					found = true
+				}
+			}
+			if !found {
+				fmt.Printf("frain: no system named '%s' in %s\n", name, configPath())
+				exit()
+			}
+		}
+	}
+	if len(systems) == 0 {
+		fmt.Println("frain: no system configured (\"frain impact -h\" for help)")
+		exit()
+	}
+
+	// dependencies may name services by alias
+	var names []string
+	seen := map[string]bool{}
+	for i, sys := range systems {
+		deps := make([]frain.Dependency, len(sys.DependsOn))
+		for j, dep := range sys.DependsOn {
+			name, err := resolveService(dep.Service)
+			if err != nil {
+				fmt.Printf("frain: system '%s': %v\n", sys.Name, err)
+				exit()
+			}
+			dep.Service = name
+			deps[j] = dep
+
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+		systems[i].DependsOn = deps
+	}
+
+	// an incident opened long ago may still be unresolved
+	fetched := fetchAvailable(names, historyStart, time.Now())
+	services := map[string]*frain.Service{}
+	for i, name := range names {
+		if fetched[i] != nil {
+			services[name] = fetched[i]
+			if *impactIgnore {
+				services[name] = frain.WithoutMaintenance(fetched[i], time.Now())
+			}
+		}
+	}
+
+	impacts := frain.BlastRadius(systems, services)
+
+	var err error
+	switch strings.ToLower(*impactFormat) {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(impacts)
+	case "txt":
+		err = frain.WriteImpactText(os.Stdout, impacts, *impactAll)
+	default:
+		err = fmt.Errorf("bad format specified '%s'", *impactFormat)
+	}
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
@@ -3,120 +3,258 @@ package main
 import (
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
 
 	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 	"github.com/mekilis/frain"
+	"golang.org/x/term"
 )
 
 var (
 	green  = color.New(color.FgGreen).Sprint
 	yellow = color.New(color.FgYellow).Sprint
 
-	config  = "Path to configuration file"
-	format  = "Select format to display query"
-	help    = "Displays this help"
-	full    = "Displays a full version of incident descriptions"
-	list    = "Lists the currently supported services"
-	quiet   = "Displays the service summary"
-	version = "Current version of frain"
+	config     = "Path to configuration file"
+	help       = "Displays this help"
+	version    = "Current version of frain"
+	list       = "Deprecated, see \"frain list\""
+	colour     = "When to use colours i.e. auto, always or never"
+	noProgress = "Disables the progress spinner"
+	width      = "Width of the output in columns, detected from the terminal when 0"
+	retries    = "Number of times a failed request to the frain backend is retried"
+	timeout    = "Time allowed for each request to the frain backend"
 
 	configFlag  = flag.String("config", "", config)
-	formatFlag  = flag.String("format", "txt", format)
 	helpFlag    = flag.Bool("help", false, help)
-	fullFlag    = flag.Bool("full", false, full)
-	listFlag    = flag.Bool("list", false, list)
-	quietFlag   = flag.Bool("quiet", false, quiet)
 	versionFlag = flag.Bool("version", false, version)
+	listFlag    = flag.Bool("list", false, list)
+	retriesFlag = flag.Int("retries", frain.DefaultRetryPolicy.Retries, retries)
+	timeoutFlag = flag.Duration("timeout", 30*time.Second, timeout)
+
+	colourFlag     = flag.String("color", "auto", colour)
+	noProgressFlag = flag.Bool("no-progress", false, noProgress)
+	widthFlag      = flag.Int("width", 0, width)
 
 	buildVersion string
 
-	subCommands = map[string]bool{
-		"incidents": true,
-	}
+	// historyStart starts the incident window of a fetch covering every incident that
+	// may still be open or change
+	historyStart = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// commands holds every frain subcommand by name
+	commands = map[string]*command{}
 )
 
+// command is a frain subcommand with its own flags and help
+type command struct {
+	name     string
+	args     string
+	summary  string
+	examples []string
+	// notes are printed after the options in the command's help
+	notes  string
+	hidden bool
+	// rawArgs passes the arguments to run without parsing any flag
+	rawArgs bool
+
+	flags *flag.FlagSet
+	run   func(args []string)
+}
+
+// register makes a command available on the command line
+func register(cmd *command) {
+	if cmd.flags == nil {
+		cmd.flags = flag.NewFlagSet(cmd.name, flag.ExitOnError)
+	}
+	cmd.flags.Usage = func() {
+		commandUsage(cmd)
+	}
+	commands[cmd.name] = cmd
+}
+
 func init() {
 	flag.StringVar(configFlag, "c", "", config)
-	flag.StringVar(formatFlag, "f", "txt", format)
 	flag.BoolVar(helpFlag, "h", false, help)
-	flag.BoolVar(listFlag, "l", false, list)
-	flag.BoolVar(quietFlag, "q", false, quiet)
 	flag.BoolVar(versionFlag, "v", false, version)
+	flag.BoolVar(listFlag, "l", false, list)
+
+	flag.Usage = usage
+
+	register(&command{
+		name:     "list",
+		summary:  "Lists the currently supported services on frain",
+		examples: []string{"frain list\t==> List the services supported by frain"},
+		run:      runList,
+	})
+	register(&command{
+		name:    "version",
+		summary: "Displays the current version of this program",
+		run: func(args []string) {
+			frain.Init()
+		},
+	})
+	register(&command{
+		name:    "help",
+		args:    "[<command>]",
+		summary: "Displays help for frain or one of its commands",
+		run:     runHelp,
+	})
+}
+
+func usage() {
+	w := new(tabwriter.Writer)
+	w.Init(os.Stdout, 2, 8, 0, '\t', tabwriter.AlignRight)
+	fmt.Fprint(w, yellow("\nUsage:"),
+		"\n\tfrain ", green("[options]"), " <command> [<args>...]",
+		"\n\tfrain ", green("[options]"), " <service>\t==> Short for frain status <service>\n",
+		yellow("\nOptions:"),
+		green("\n\t-c <path>,\t--config=<path>\t"), "Specifies path to configuration file with a\n\t\t\tlist of services to check",
+		green("\n\t\t--color=<when>\t"), "Colours the output auto(matically), always or never",
+		green("\n\t-h,\t--help\t"), "Displays this help message",
+		green("\n\t-l,\t--list\t"), "Same as frain list, kept for older scripts",
+		green("\n\t\t--no-progress\t"), "Disables the progress spinner",
+		green("\n\t\t--retries=<n>\t"), "Retries failed requests n times (default 3)",
+		green("\n\t\t--timeout=<duration>\t"), "Gives up on a request after this long (default 30s)",
+		green("\n\t-v,\t--version\t"), "Displays the current version of this program",
+		green("\n\t\t--width=<n>\t"), "Lays out reports n columns wide instead of the terminal width\n",
+		yellow("\nCommands:"))
+
+	for _, cmd := range sortedCommands() {
+		fmt.Fprint(w, "\n\t", green(cmd.name), "\t", cmd.summary)
+	}
+
+	fmt.Fprint(w, "\n\nRun \"frain help <command>\" for the options of a command.\n",
+		yellow("\nExamples:"))
+	for _, cmd := range sortedCommands() {
+		for _, example := range cmd.examples {
+			fmt.Fprint(w, "\n\t", example)
+		}
+	}
+	fmt.Fprintln(w)
 
-	flag.Usage = func() {
-		w := new(tabwriter.Writer)
-		w.Init(os.Stdout, 2, 8, 0, '\t', tabwriter.AlignRight)
-		fmt.Fprint(w, yellow("\nUsage:"),
-			"\n\tfrain ", green("[options]"), " <args>...\n",
-			yellow("\nOptions:"),
-			green("\n\t\t--full\t"), "Displays the full version of incident descriptions",
-			green("\n\t-c <path>,\t--config=<path>\t"), "Specifies path to configuration file with a\n\t\t\tlist of services to check",
-			green("\n\t-f <format>,\t--format=<format>\t"), "Specifies result output format i.e. txt, json\n\t\t\tor xml (txt by default)",
-			green("\n\t-h,\t--help\t"), "Displays this help message",
-			green("\n\t-l,\t--list\t"), "Lists the currently supported services on frain",
-			green("\n\t-q <service>,\t--quiet <service>\t"), "Displays just the summary for specified service",
-			green("\n\t-v,\t--version\t"), "Displays the current version of this program\n",
-			yellow("\nArgs:"),
-			"\n\t<service>\n\t<service> ", green("incidents"),
-			"\n\t<service> ", green("incidents <start time> <end time>\n\n"),
-			"Note that both start and end times have the format YYYY-MM-DD\n",
-			yellow("\nExamples:"),
-			"\n\tfrain github\t==> Fetch report for github",
-			"\n\tfrain -q github\t==> Summarize fetched result for github",
-			"\n\tfrain github incidents\t==> Fetch only incident reports",
-			"\n\tfrain github incidents 2019-01-12\t==> Fetch incidents from start date",
-			"\n\tfrain github incidents 2019-01-12 2019-05-05\t==> Fetch incidents from start to end dates\n")
-
-		w.Flush()
+	w.Flush()
+}
+
+func commandUsage(cmd *command) {
+	fmt.Fprint(os.Stdout, yellow("\nUsage:"),
+		"\n\tfrain ", cmd.name, " ", green("[options]"), " ", cmd.args, "\n\n",
+		cmd.summary, "\n")
+
+	n := 0
+	cmd.flags.VisitAll(func(*flag.Flag) { n++ })
+	if n > 0 {
+		fmt.Println(yellow("\nOptions:"))
+		cmd.flags.SetOutput(os.Stdout)
+		cmd.flags.PrintDefaults()
+	}
+
+	if cmd.notes != "" {
+		fmt.Print("\n", cmd.notes, "\n")
+	}
+}
+
+func sortedCommands() []*command {
+	var cmds []*command
+	for _, cmd := range commands {
+		if !cmd.hidden {
+			cmds = append(cmds, cmd)
+		}
 	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].name < cmds[j].name })
+
+	return cmds
 }
 
 func main() {
-	flag.Parse()
+	global, rest := splitGlobalArgs(os.Args[1:])
+	flag.CommandLine.Parse(global)
 	setupVerInfo()
 	parseFlagOptions()
 
-	flagArgs := flag.Args()
-	format := strings.ToLower(*formatFlag)
-
-	if format != "txt" && format != "json" && format != "xml" {
-		fmt.Printf("frain: bad format specified '%s' (\"frain help\" for help)\n", format)
+	if len(rest) == 0 {
+		fmt.Println("frain: no command or service specified (\"frain help\" for help)")
 		exit()
 	}
 
-	if len(os.Args) < 2 || len(flagArgs) == 0 {
-		fmt.Println("frain: no service specified (\"frain help\" for help)")
-		exit()
+	if cmd, ok := commands[rest[0]]; ok {
+		if cmd.rawArgs {
+			cmd.run(rest[1:])
+			return
+		}
+		cmd.run(parseArgs(cmd.flags, rest[1:]))
+		return
 	}
 
-	subCommand, startTime, endTime, err := parseFlagParams(flagArgs)
-	if err != nil {
-		fmt.Println("frain:", err, "(\"frain help\" for help)")
-		exit()
+	// frain [options] <service> [incidents [<start time> [<end time>]]]
+	// frain [options] <service> maintenance
+	for i, a := range rest {
+		if (a == "incidents" || a == "maintenance") && i > 0 && !takesValue(commands[a].flags, rest[i-1]) {
+			args := append(append([]string{}, rest[:i]...), rest[i+1:]...)
+			commands[a].run(parseArgs(commands[a].flags, args))
+			return
+		}
 	}
+	commands["status"].run(parseArgs(commands["status"].flags, rest))
+}
 
-	name := strings.ToLower(flagArgs[0])
-	report, err := getReport(name, format, startTime, endTime)
-	if err != nil {
-		fmt.Println("frain:", err)
-		exit()
+// splitGlobalArgs separates the leading global options from the command and its
+// arguments. Any other leading option is left for the status shorthand.
+func splitGlobalArgs(args []string) ([]string, []string) {
+	i := 0
+	for i < len(args) && strings.HasPrefix(args[i], "-") {
+		name := strings.TrimLeft(args[i], "-")
+		hasValue := strings.Contains(name, "=")
+		if hasValue {
+			name = name[:strings.Index(name, "=")]
+		}
+
+		f := flag.Lookup(name)
+		if f == nil {
+			break
+		}
+		i++
+		if !hasValue && !isBoolFlag(f) {
+			i++
+		}
+	}
+	if i > len(args) {
+		i = len(args)
 	}
 
-	if len(flagArgs) < 2 {
-		report.All(*quietFlag, *fullFlag)
-	} else {
+	return args[:i], args[i:]
+}
 
-		switch subCommand {
+// takesValue reports whether arg is a flag of fs whose value is the next argument
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	if !strings.HasPrefix(arg, "-") || strings.Contains(arg, "=") {
+		return false
+	}
+	f := fs.Lookup(strings.TrimLeft(arg, "-"))
 
-		case "incidents":
-			report.Incidents(*quietFlag, *fullFlag)
+	return f != nil && !isBoolFlag(f)
+}
 
+// parseArgs parses the flags of a command wherever they appear among its arguments and
+// returns the remaining positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		args = fs.Args()
+		if len(args) == 0 {
+			break
 		}
+		positional = append(positional, args[0])
+		args = args[1:]
 	}
+
+	return positional
 }
 
 func setupVerInfo() {
@@ -128,6 +266,19 @@ func setupVerInfo() {
 }
 
 func parseFlagOptions() {
+	if err := setColour(*colourFlag); err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+	frain.DefaultClient.HTTPClient = &http.Client{Timeout: *timeoutFlag}
+	frain.DefaultClient.Retry.Retries = *retriesFlag
+
+	// -l and --list predate the list command
+	if *listFlag {
+		runList(nil)
+		exit()
+	}
+
 	if *versionFlag {
 		frain.Init()
 		exit()
@@ -138,141 +289,221 @@ func parseFlagOptions() {
 		flag.Usage()
 		exit()
 	}
+}
 
-	if *listFlag {
+func runHelp(args []string) {
+	if len(args) == 0 {
 		frain.Init()
-		listServices()
+		flag.Usage()
+		return
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Printf("frain: unknown command '%s' (\"frain help\" for help)\n", args[0])
 		exit()
 	}
+	commandUsage(cmd)
+}
+
+// loadConfig reads the configuration file given by the config flag, falling back to
+// the default location. A missing default configuration file is not an error.
+func loadConfig() *frain.Config {
+	path := configPath()
+	if _, err := os.Stat(path); *configFlag == "" && (path == "" || os.IsNotExist(err)) {
+		return &frain.Config{}
+	}
 
-	if len(*configFlag) != 0 {
-		fmt.Println("-c flag set. this feature has not yet been implemented. please check again later.")
+	cfg, err := frain.LoadConfig(path)
+	if err != nil {
+		fmt.Println("frain:", err)
 		exit()
 	}
+
+	return cfg
 }
 
-func progress(c chan int) {
-	s := "Please wait while fetching data"
-	dots := []string{".  ", ".. ", "..."}
-	for {
-		select {
-		case <-c:
-			return
-		default:
-			for _, d := range dots {
-				fmt.Print(s, d)
-				time.Sleep(time.Second)
-				fmt.Print("\r \r")
-			}
+// configPath returns the configuration file given by the config flag, or the default
+// location when the flag is unset
+func configPath() string {
+	if *configFlag != "" {
+		return *configFlag
+	}
 
-		}
+	return frain.DefaultConfigPath()
+}
+
+// resolveService maps a service name given on the command line, which may be an alias
+// or differ in case and separators, to the name frain knows the service by
+func resolveService(name string) (string, error) {
+	cfg := loadConfig()
+	known := cachedServiceList(frain.DefaultClient)
+	if len(known) > 0 {
+		known = append(known, cfg.ProvidedServices()...)
+	}
+
+	name, err := frain.ResolveService(name, known, cfg.ServiceAliases())
+	if e, ok := err.(*frain.UnknownServiceError); ok && len(e.Suggestions) == 0 {
+		return "", fmt.Errorf("%v (see \"frain list\")", err)
 	}
+
+	return name, err
 }
 
-func clear() {
-	cls := "                                        "
-	fmt.Print("\r \r")
-	fmt.Print(cls)
-	fmt.Print("\r \r")
+// serviceArgs resolves the services named on the command line, the configured services
+// when none is
+func serviceArgs(args []string, cmd string) []string {
+	names := args
+	if len(names) == 0 {
+		names = loadConfig().ServiceNames()
+	}
+	if len(names) == 0 {
+		fmt.Printf("frain: no service specified for %s (\"frain %s -h\" for help)\n", cmd, cmd)
+		exit()
+	}
+
+	for i, name := range names {
+		resolved, err := resolveService(name)
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		names[i] = resolved
+	}
+
+	return names
 }
 
-func listServices() {
-	var c = make(chan int)
-	go progress(c)
+// getService fetches a service from the provider configured for it, the frain backend
+// unless the configuration file says otherwise, and adds the results of its probes as
+// components. The probes run while the service is fetched, so they only hold it up
+// when they take longer.
+func getService(name string, startTime, endTime time.Time) (*frain.Service, error) {
+	cfg := loadConfig()
+
+	var results []frain.ProbeResult
+	probed := make(chan struct{})
+	go func() {
+		defer close(probed)
+		if probes := cfg.Probes(name); len(probes) > 0 {
+			results = frain.RunProbes(probes)
+		}
+	}()
 
-	sl, err := frain.GetServiceList()
-	c <- 0
-	clear()
+	var service *frain.Service
+	var err error
+	if p := cfg.Provider(name); p != nil {
+		service, err = p.GetService(name, startTime, endTime)
+	} else {
+		service, err = frain.GetService(name, startTime, endTime)
+	}
 	if err != nil {
-		fmt.Println(err)
-		return
+		return nil, err
 	}
 
-	size := len(sl)
-	if size == 0 {
-		fmt.Println("\nNo service supported at the moment.")
-		return
+	<-probed
+	if len(results) > 0 {
+		frain.AddProbeResults(service, results)
 	}
 
-	fmt.Println("\nServices currently supported are:")
-	for _, s := range sl {
-		fmt.Printf("\t%s\n", s)
-	}
+	return service, nil
 }
 
-func parseFlagParams(flagArgs []string) (string, time.Time, time.Time, error) {
-	subCommand := ""
-	startTime, _ := time.Parse("2006-01-02", "1970-01-01") // iso layout
-	endTime := time.Now()
-	flagArgsLen := len(flagArgs)
+// showProgress reports whether the spinner is drawn. It is drawn on stderr and only
+// when stderr is a terminal so that it never ends up in redirected output.
+func showProgress() bool {
+	return !*noProgressFlag && isTerminal(os.Stderr)
+}
 
-	if flagArgsLen > 1 {
-		subCommand = flagArgs[1]
-		if _, ok := subCommands[subCommand]; !ok {
-			return subCommand, startTime, endTime, fmt.Errorf("unknown subcommand specified for %s: '%s'", flagArgs[0], flagArgs[1])
-		}
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+func progress(c chan int) {
+	if !showProgress() {
+		<-c
+		return
 	}
 
-	if flagArgsLen > 2 {
-		sTime, err := frain.CleanTimeArg(flagArgs[2])
-		if err != nil {
-			return subCommand, startTime, endTime, fmt.Errorf("start time error. %v", err)
-		}
-		startTime, err = time.Parse("2006-01-02", sTime)
-		if err != nil {
-			return subCommand, startTime, endTime, fmt.Errorf("bad format specified for start time: %v", flagArgs[2])
+	s := "Please wait while fetching data"
+	dots := []string{".  ", ".. ", "..."}
+	for i := 0; ; i++ {
+		fmt.Fprint(os.Stderr, "\r", s, dots[i%len(dots)])
+		select {
+		case <-c:
+			return
+		case <-time.After(time.Second):
 		}
 	}
+}
 
-	if flagArgsLen > 3 {
-		eTime, err := frain.CleanTimeArg(flagArgs[3])
-		if err != nil {
-			return subCommand, startTime, endTime, fmt.Errorf("end time error. %v", err)
-		}
-		endTime, err = time.Parse("2006-01-02", eTime)
-		if err != nil {
-			return subCommand, startTime, endTime, fmt.Errorf("bad format specified for end time: %v", flagArgs[3])
-		}
+func clear() {
+	if !showProgress() {
+		return
 	}
 
-	return subCommand, startTime, endTime, nil
+	cls := "                                        "
+	fmt.Fprint(os.Stderr, "\r", cls, "\r")
 }
 
-func getReport(name, format string, startTime, endTime time.Time) (frain.Report, error) {
-	var report frain.Report
-	var page frain.Page
-	errFmt := "report feature has not been implemented yet, please check back later in a future release"
+// terminalWidth returns the number of columns reports may take: the --width flag, else
+// the width of the terminal, else 0 for the default layout of redirected output
+func terminalWidth() int {
+	if *widthFlag > 0 {
+		return *widthFlag
+	}
+	if !isTerminal(os.Stdout) {
+		return 0
+	}
 
-	switch format {
-	case "json":
-		return nil, fmt.Errorf("json %v", errFmt)
+	w, _, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil {
+		return 0
+	}
 
-	case "xml":
-		return nil, fmt.Errorf("xml %v", errFmt)
+	return w
+}
 
-	case "txt":
-		report = frain.Text{
-			Data: &page,
-		}
+// setColour enables or disables coloured output. In auto mode colours are used when
+// stdout is a terminal and the NO_COLOR environment variable is not set.
+func setColour(mode string) error {
+	switch strings.ToLower(mode) {
+	case "always":
+		color.NoColor = false
+	case "never":
+		color.NoColor = true
+	case "auto":
+		color.NoColor = os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" || !isTerminal(os.Stdout)
+	default:
+		return fmt.Errorf("bad color mode '%s', expected auto, always or never", mode)
 	}
 
+	return nil
+}
+
+func runList(args []string) {
 	var c = make(chan int)
 	go progress(c)
 
-	service, err := frain.GetService(name, startTime, endTime)
-	c <- 1
+	sl, err := frain.GetServiceList()
+	c <- 0
 	clear()
 	if err != nil {
-		return nil, err
+		fmt.Println(err)
+		return
 	}
 
-	if strings.ToLower(service.Name) != name {
-		return nil, fmt.Errorf("'%s' is not a recognized service on frain (see \"frain --list\")", name)
+	size := len(sl)
+	if size == 0 {
+		fmt.Println("\nNo service supported at the moment.")
+		return
 	}
 
-	page.Name, page.Service = name, service
-
-	return report, nil
+	sort.Strings(sl)
+	fmt.Println("\nServices currently supported are:")
+	for _, s := range sl {
+		fmt.Printf("\t%s\n", s)
+	}
 }
 
 func exit() {
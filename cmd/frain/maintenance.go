@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	maintenanceFlags = flag.NewFlagSet("maintenance", flag.ExitOnError)
+
+	maintenanceAll    = maintenanceFlags.Bool("all", false, "Also list the completed maintenances")
+	maintenanceFormat = maintenanceFlags.String("format", "txt", "Output format i.e. txt or json")
+)
+
+func init() {
+	register(&command{
+		name:    "maintenance",
+		args:    "<service>",
+		summary: "Displays the upcoming and in progress maintenances of a service",
+		examples: []string{
+			"frain github maintenance\t==> Show when github is down for maintenance (frain maintenance github)",
+		},
+		flags: maintenanceFlags,
+		run:   runMaintenance,
+	})
+}
+
+func runMaintenance(args []string) {
+	if len(args) == 0 {
+		fmt.Println("frain: no service specified (\"frain help\" for help)")
+		exit()
+	}
+	if len(args) > 1 {
+		fmt.Printf("frain: unexpected argument '%s' (\"frain help\" for help)\n", args[1])
+		exit()
+	}
+
+	now := time.Now()
+	// the whole history keeps the snapshot recorded by the fetch complete
+	services, err := fetchServices(args, historyStart, now)
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+	service := services[0]
+
+	switch strings.ToLower(*maintenanceFormat) {
+	case "json":
+		maintenances := service.PlannedMaintenances(now)
+		if *maintenanceAll {
+			maintenances = service.Maintenances
+		}
+		if maintenances == nil {
+			maintenances = []frain.Maintenance{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(maintenances)
+	case "txt":
+		err = frain.WriteMaintenanceText(os.Stdout, service, now, *maintenanceAll)
+	default:
+		err = fmt.Errorf("bad format specified '%s'", *maintenanceFormat)
+	}
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	probeFlags = flag.NewFlagSet("probe", flag.ExitOnError)
+
+	probeFormat   = probeFlags.String("format", "txt", "Output format i.e. txt or json")
+	probeInterval = probeFlags.Duration("interval", 0, "Run the probes again after this long, until interrupted")
+)
+
+func init() {
+	register(&command{
+		name:     "probe",
+		args:     "[<service>...]",
+		summary:  "Checks the endpoints of services ourselves",
+		examples: []string{"frain probe github\t==> Run the probes configured for github"},
+		notes: "Probes are listed under \"probes\" of a service in the configuration file. They\n" +
+			"run whenever the service is fetched and show as components named after their target.\n" +
+			"\"frain watch\" runs them periodically along with the service, recording the\n" +
+			"snapshots \"frain correlate\" compares.",
+		flags: probeFlags,
+		run:   runProbe,
+	})
+}
+
+func runProbe(args []string) {
+	cfg := loadConfig()
+	names := serviceArgs(args, "probe")
+
+	// a JSON document per round carries its own times
+	stamped := *probeInterval > 0 && strings.ToLower(*probeFormat) == "txt"
+	for {
+		if stamped {
+			fmt.Println(time.Now().Format(time.Stamp))
+		}
+		probeServices(cfg, names)
+		if *probeInterval <= 0 {
+			return
+		}
+		if stamped {
+			fmt.Println()
+		}
+		time.Sleep(*probeInterval)
+	}
+}
+
+func probeServices(cfg *frain.Config, names []string) {
+	var probes []frain.ServiceProbes
+	for _, name := range names {
+		if p := cfg.Probes(name); len(p) > 0 {
+			probes = append(probes, frain.ServiceProbes{Service: name, Results: frain.RunProbes(p)})
+		}
+	}
+
+	var err error
+	switch strings.ToLower(*probeFormat) {
+	case "json":
+		if probes == nil {
+			probes = []frain.ServiceProbes{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(probes)
+	case "txt":
+		err = frain.WriteProbeText(os.Stdout, probes)
+	default:
+		err = fmt.Errorf("bad format specified '%s'", *probeFormat)
+	}
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
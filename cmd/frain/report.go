@@ -0,0 +1,263 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+// reportFlags are the output options shared by the status, incidents and components
+// commands
+type reportFlags struct {
+	format         *string
+	full           *bool
+	quiet          *bool
+	save           *string
+	table          *string
+	template       *string
+	templateString *string
+}
+
+func newReportFlags(fs *flag.FlagSet) *reportFlags {
+	rf := &reportFlags{
+		format:         fs.String("format", "txt", "Result output format i.e. txt, json, xml, csv, tsv, template, atom or rss"),
+		full:           fs.Bool("full", false, "Displays the full version of incident descriptions"),
+		quiet:          fs.Bool("quiet", false, "Displays just the summary"),
+		save:           fs.String("save", "", "Saves a snapshot of the fetched service to a file for frain diff"),
+		table:          fs.String("table", frain.TableIncidents, "Rows written in csv and tsv formats i.e. incidents or components"),
+		template:       fs.String("template", "", "Path to a Go text/template file used by the template format"),
+		templateString: fs.String("template-string", "", "Inline Go text/template used by the template format"),
+	}
+	fs.StringVar(rf.format, "f", "txt", "Short for --format")
+	fs.BoolVar(rf.quiet, "q", false, "Short for --quiet")
+
+	return rf
+}
+
+func (rf *reportFlags) options() frain.Options {
+	return frain.Options{
+		Quiet: *rf.quiet,
+		Full:  *rf.full,
+		Width: terminalWidth(),
+	}
+}
+
+var (
+	statusFlags     = flag.NewFlagSet("status", flag.ExitOnError)
+	incidentsFlags  = flag.NewFlagSet("incidents", flag.ExitOnError)
+	componentsFlags = flag.NewFlagSet("components", flag.ExitOnError)
+
+	statusReport     = newReportFlags(statusFlags)
+	incidentsReport  = newReportFlags(incidentsFlags)
+	componentsReport = newReportFlags(componentsFlags)
+)
+
+func init() {
+	register(&command{
+		name:    "status",
+		args:    "<service>",
+		summary: "Displays the components and incidents of a service",
+		examples: []string{
+			"frain github\t==> Fetch report for github (frain status github)",
+			"frain -q github\t==> Summarize fetched result for github",
+			"frain -f csv --table=components github\t==> Export github components as CSV",
+		},
+		flags: statusFlags,
+		run: func(args []string) {
+			runReport(statusReport, args, frain.Report.All)
+		},
+	})
+	register(&command{
+		name:    "incidents",
+		args:    "<service> [<start time> [<end time>]]",
+		summary: "Displays the incident history of a service",
+		examples: []string{
+			"frain github incidents\t==> Fetch only incident reports (frain incidents github)",
+			"frain incidents github 2019-01-12\t==> Fetch incidents from start date",
+			"frain incidents github 2019-01-12 2019-05-05\t==> Fetch incidents from start to end dates",
+		},
+		notes: "Note that both start and end times have the format YYYY-MM-DD",
+		flags: incidentsFlags,
+		run: func(args []string) {
+			runReport(incidentsReport, args, frain.Report.Incidents)
+		},
+	})
+	register(&command{
+		name:     "components",
+		args:     "<service>",
+		summary:  "Displays the components of a service",
+		examples: []string{"frain components -q github\t==> Summarize the components of github"},
+		flags:    componentsFlags,
+		run: func(args []string) {
+			runReport(componentsReport, args, frain.Report.Components)
+		},
+	})
+}
+
+func runReport(rf *reportFlags, args []string, render func(frain.Report, io.Writer, frain.Options) error) {
+	if len(args) == 0 {
+		fmt.Println("frain: no service specified (\"frain help\" for help)")
+		exit()
+	}
+
+	startTime, endTime, err := parseTimeArgs(args[1:])
+	if err != nil {
+		fmt.Println("frain:", err, "(\"frain help\" for help)")
+		exit()
+	}
+
+	format := strings.ToLower(*rf.format)
+	switch format {
+	case "txt", "json", "xml", "csv", "tsv", "template", frain.FeedAtom, frain.FeedRSS:
+	default:
+		fmt.Printf("frain: bad format specified '%s' (\"frain help\" for help)\n", format)
+		exit()
+	}
+
+	if t := *rf.table; t != frain.TableIncidents && t != frain.TableComponents {
+		fmt.Printf("frain: bad table specified '%s' (\"frain help\" for help)\n", t)
+		exit()
+	}
+
+	name, err := resolveService(args[0])
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+
+	report, err := getReport(rf, name, format, startTime, endTime)
+	if err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+
+	if err := render(report, os.Stdout, rf.options()); err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
+
+func parseTimeArgs(args []string) (time.Time, time.Time, error) {
+	startTime, _ := time.Parse("2006-01-02", "1970-01-01") // iso layout
+	endTime := time.Now()
+	argsLen := len(args)
+
+	if argsLen > 2 {
+		return startTime, endTime, fmt.Errorf("unexpected argument '%s'", args[2])
+	}
+
+	if argsLen > 0 {
+		sTime, err := frain.CleanTimeArg(args[0])
+		if err != nil {
+			return startTime, endTime, fmt.Errorf("start time error. %v", err)
+		}
+		startTime, err = time.Parse("2006-01-02", sTime)
+		if err != nil {
+			return startTime, endTime, fmt.Errorf("bad format specified for start time: %v", args[0])
+		}
+	}
+
+	if argsLen > 1 {
+		eTime, err := frain.CleanTimeArg(args[1])
+		if err != nil {
+			return startTime, endTime, fmt.Errorf("end time error. %v", err)
+		}
+		endTime, err = time.Parse("2006-01-02", eTime)
+		if err != nil {
+			return startTime, endTime, fmt.Errorf("bad format specified for end time: %v", args[1])
+		}
+	}
+
+	return startTime, endTime, nil
+}
+
+func getReport(rf *reportFlags, name, format string, startTime, endTime time.Time) (frain.Report, error) {
+	var report frain.Report
+	var page frain.Page
+	errFmt := "report feature has not been implemented yet, please check back later in a future release"
+
+	switch format {
+	case "json":
+		return nil, fmt.Errorf("json %v", errFmt)
+
+	case "xml":
+		return nil, fmt.Errorf("xml %v", errFmt)
+
+	case "csv":
+		report = frain.CSV{
+			Data:  &page,
+			Table: *rf.table,
+		}
+
+	case "tsv":
+		report = frain.CSV{
+			Data:  &page,
+			Comma: '\t',
+			Table: *rf.table,
+		}
+
+	case frain.FeedAtom, frain.FeedRSS:
+		report = frain.FeedReport{
+			Data:   &page,
+			Format: format,
+		}
+
+	case "template":
+		text, name := *rf.templateString, "template"
+		if *rf.template != "" {
+			data, err := ioutil.ReadFile(*rf.template)
+			if err != nil {
+				return nil, err
+			}
+			text, name = string(data), filepath.Base(*rf.template)
+		}
+		if text == "" {
+			return nil, errors.New("template format requires --template or --template-string")
+		}
+
+		t, err := frain.NewTemplate(&page, name, text)
+		if err != nil {
+			return nil, err
+		}
+		report = t
+
+	case "txt":
+		report = frain.Text{
+			Data: &page,
+		}
+	}
+
+	var c = make(chan int)
+	go progress(c)
+
+	service, err := getService(name, startTime, endTime)
+	c <- 1
+	clear()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.ToLower(service.Name) != name {
+		return nil, fmt.Errorf("'%s' is not a recognized service on frain (see \"frain list\")", name)
+	}
+
+	page.Name, page.Service = name, service
+
+	now := time.Now()
+	recordSnapshot(service, startTime, endTime, now)
+	if *rf.save != "" {
+		if err := frain.SaveSnapshot(*rf.save, service, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
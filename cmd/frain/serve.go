@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	serveFlags = flag.NewFlagSet("serve", flag.ExitOnError)
+
+	serveAddr    = serveFlags.String("addr", "127.0.0.1:8000", "Address to listen on")
+	serveRefresh = serveFlags.Duration("refresh", 5*time.Minute, "Time the fetched services are served before being fetched again")
+	serveSince   = serveFlags.Duration("since", 7*24*time.Hour, "Publish the incident updates posted this long ago and since")
+)
+
+func init() {
+	register(&command{
+		name:     "serve",
+		args:     "[<service>...]",
+		summary:  "Publishes the incidents of services as a feed over HTTP",
+		examples: []string{"frain serve --addr :8000\t==> Serve a feed of the configured services on /feed.xml"},
+		notes: "The feed is served on /feed.xml as Atom, or as RSS with ?format=rss. Services\n" +
+			"default to those listed in the configuration file.",
+		flags: serveFlags,
+		run:   runServe,
+	})
+}
+
+func runServe(args []string) {
+	names := serviceArgs(args, "serve")
+	// nobody watches the spinner of a server
+	*noProgressFlag = true
+
+	// updates may be posted long after their incident was created
+	var (
+		mu         sync.Mutex
+		services   = fetchAvailable(names, historyStart, time.Now())
+		fetched    = time.Now()
+		refreshing bool
+	)
+	// stale services are served while they are fetched again in the background, so
+	// that a slow vendor holds up no request
+	fetch := func() []*frain.Service {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !refreshing && time.Since(fetched) >= *serveRefresh {
+			refreshing = true
+			go func() {
+				fresh := fetchAvailable(names, historyStart, time.Now())
+				mu.Lock()
+				services, fetched, refreshing = fresh, time.Now(), false
+				mu.Unlock()
+			}()
+		}
+		return services
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/feed.xml", frain.FeedHandler(fetch, frain.FeedOptions{MaxAge: *serveSince}))
+
+	fmt.Printf("Serving the feed of %d service(s) on http://%s/feed.xml\n", len(names), *serveAddr)
+	if err := http.ListenAndServe(*serveAddr, mux); err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
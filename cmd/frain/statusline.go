@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	statusLineFlags = flag.NewFlagSet("statusline", flag.ExitOnError)
+
+	statusLinePlain  = statusLineFlags.Bool("plain", false, "Print the status line without colours")
+	statusLineMaxAge = statusLineFlags.Duration("max-age", 0, "Treat cached services older than this as unknown")
+)
+
+func init() {
+	register(&command{
+		name:     "statusline",
+		args:     "[<service>...]",
+		summary:  "Prints a one-line indicator for shell prompts and tmux",
+		examples: []string{"frain statusline github circleci fastly\t==> Print a one-line indicator such as GH✓ CI⚠ FS✗"},
+		notes: "The status line is built from the snapshot cache without any network request, so\n" +
+			"a service shows as unknown (?) until it has been fetched at least once, e.g. with\n" +
+			"\"frain <service>\" or \"frain watch\". As elsewhere, colours are only used on a\n" +
+			"terminal unless \"frain --color=always statusline\" is run, e.g. in a shell prompt.",
+		flags: statusLineFlags,
+		run:   runStatusLine,
+	})
+}
+
+func runStatusLine(args []string) {
+	names := args
+	if len(names) == 0 {
+		names = loadConfig().ServiceNames()
+	}
+	if len(names) == 0 {
+		fmt.Println("frain: no service specified for statusline (\"frain statusline -h\" for help)")
+		exit()
+	}
+
+	// no service list is fetched here, only aliases are expanded
+	aliases := loadConfig().ServiceAliases()
+	for i, name := range names {
+		names[i], _ = frain.ResolveService(name, nil, aliases)
+	}
+
+	dir := frain.DefaultSnapshotDir()
+	services := map[string]*frain.Service{}
+	for _, name := range names {
+		snap, err := frain.LatestSnapshot(dir, name)
+		if err != nil || (*statusLineMaxAge > 0 && time.Since(snap.TakenAt) > *statusLineMaxAge) {
+			continue
+		}
+		services[name] = snap.Service
+	}
+	fmt.Println(frain.StatusLine(names, services, !*statusLinePlain))
+}
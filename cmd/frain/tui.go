@@ -0,0 +1,375 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mekilis/frain"
+	"github.com/rivo/tview"
+)
+
+// browser is the full-screen terminal UI started by "frain tui"
+type browser struct {
+	app        *tview.Application
+	pages      *tview.Flex
+	list       *tview.List
+	components *tview.Table
+	incidents  *tview.TreeView
+	search     *tview.InputField
+	footer     *tview.TextView
+
+	mu        sync.Mutex
+	names     []string
+	services  map[string]*frain.Service
+	errs      map[string]error
+	filter    string
+	selected  string
+	fetchedAt time.Time
+}
+
+var (
+	tuiFlags = flag.NewFlagSet("tui", flag.ExitOnError)
+
+	tuiRefresh = tuiFlags.Duration("refresh", 0, "Refresh the services periodically (manual refresh with 'r' when unset)")
+)
+
+func init() {
+	register(&command{
+		name:     "tui",
+		args:     "[<service>...]",
+		summary:  "Browses services and incidents in a full-screen terminal UI",
+		examples: []string{"frain tui --refresh 1m\t==> Browse configured services full screen"},
+		notes: "Services default to those listed in the configuration file, or all supported\n" +
+			"services when none is configured.\n\n" +
+			"Keys:\n" +
+			"\tTab\tswitch between the service list, components and incidents\n" +
+			"\tEnter\texpand or collapse the update timeline of an incident\n" +
+			"\t/\tsearch services and incidents, Esc clears the search\n" +
+			"\tr\trefresh\n" +
+			"\tq\tquit",
+		flags: tuiFlags,
+		run:   runTUI,
+	})
+}
+
+func runTUI(args []string) {
+	names := args
+	if len(names) == 0 {
+		names = loadConfig().ServiceNames()
+	}
+	if len(names) == 0 {
+		sl, err := frain.GetServiceList()
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		names = sl
+	}
+	for i, name := range names {
+		resolved, err := resolveService(name)
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		names[i] = resolved
+	}
+	sort.Strings(names)
+
+	b := newBrowser(names)
+	go b.fetch()
+	if *tuiRefresh > 0 {
+		go func() {
+			for range time.Tick(*tuiRefresh) {
+				b.fetch()
+			}
+		}()
+	}
+
+	if err := b.app.Run(); err != nil {
+		fmt.Println("frain:", err)
+		exit()
+	}
+}
+
+func newBrowser(names []string) *browser {
+	b := &browser{
+		app:        tview.NewApplication(),
+		list:       tview.NewList().ShowSecondaryText(false),
+		components: tview.NewTable().SetSelectable(true, false),
+		incidents:  tview.NewTreeView(),
+		search:     tview.NewInputField().SetLabel("/"),
+		footer:     tview.NewTextView().SetDynamicColors(true),
+		names:      names,
+		services:   map[string]*frain.Service{},
+		errs:       map[string]error{},
+	}
+
+	b.list.SetBorder(true).SetTitle(" Services ")
+	b.components.SetBorder(true).SetTitle(" Components ")
+	b.incidents.SetBorder(true).SetTitle(" Incidents ")
+	b.incidents.SetRoot(tview.NewTreeNode("")).SetTopLevel(1)
+
+	b.list.SetChangedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		if index >= 0 && index < len(b.visibleNames()) {
+			b.selected = b.visibleNames()[index]
+			b.showService()
+		}
+	})
+	b.incidents.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+
+	b.search.SetChangedFunc(func(text string) {
+		b.filter = strings.ToLower(text)
+		b.showList()
+	})
+	b.search.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			b.search.SetText("")
+		}
+		b.pages.RemoveItem(b.search)
+		b.app.SetFocus(b.list)
+	})
+
+	right := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(b.components, 0, 1, false).
+		AddItem(b.incidents, 0, 2, false)
+	main := tview.NewFlex().
+		AddItem(b.list, 24, 0, true).
+		AddItem(right, 0, 1, false)
+	b.pages = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(main, 0, 1, true).
+		AddItem(b.footer, 1, 0, false)
+
+	focus := []tview.Primitive{b.list, b.components, b.incidents}
+	b.app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if b.search.HasFocus() {
+			return event
+		}
+
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			step := 1
+			if event.Key() == tcell.KeyBacktab {
+				step = len(focus) - 1
+			}
+			for i, p := range focus {
+				if p.HasFocus() {
+					b.app.SetFocus(focus[(i+step)%len(focus)])
+					break
+				}
+			}
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'q':
+			b.app.Stop()
+			return nil
+		case 'r':
+			go b.fetch()
+			return nil
+		case '/':
+			b.pages.AddItem(b.search, 1, 0, true)
+			b.app.SetFocus(b.search)
+			return nil
+		}
+
+		return event
+	})
+
+	b.app.SetRoot(b.pages, true)
+	b.showList()
+	return b
+}
+
+// fetch reloads every service, updating the screen once they all returned
+func (b *browser) fetch() {
+	b.app.QueueUpdateDraw(func() {
+		b.footer.SetText(" Fetching services...")
+	})
+
+	startTime, _ := time.Parse("2006-01-02", "1970-01-01")
+	var wg sync.WaitGroup
+	for _, name := range b.names {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			service, err := getService(name, startTime, time.Now())
+			if err == nil && strings.ToLower(service.Name) != name {
+				err = fmt.Errorf("'%s' is not a recognized service on frain", name)
+			}
+
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if err != nil {
+				b.errs[name] = err
+				return
+			}
+			delete(b.errs, name)
+			b.services[name] = service
+			recordSnapshot(service, startTime, time.Now(), time.Now())
+		}(name)
+	}
+	wg.Wait()
+
+	b.app.QueueUpdateDraw(func() {
+		b.fetchedAt = time.Now()
+		b.showList()
+	})
+}
+
+func (b *browser) visibleNames() []string {
+	if b.filter == "" {
+		return b.names
+	}
+
+	var names []string
+	for _, name := range b.names {
+		if strings.Contains(name, b.filter) || len(b.matchingIncidents(name)) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func (b *browser) matchingIncidents(name string) []frain.Incident {
+	b.mu.Lock()
+	service := b.services[name]
+	b.mu.Unlock()
+	if service == nil {
+		return nil
+	}
+
+	var incidents []frain.Incident
+	for _, i := range service.Incidents {
+		if b.filter == "" || strings.Contains(name, b.filter) || incidentMatches(i, b.filter) {
+			incidents = append(incidents, i)
+		}
+	}
+
+	return incidents
+}
+
+func incidentMatches(i frain.Incident, filter string) bool {
+	if strings.Contains(strings.ToLower(i.Name), filter) {
+		return true
+	}
+	for _, u := range i.IncidentUpdates {
+		if strings.Contains(strings.ToLower(u.Body), filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *browser) showList() {
+	selected, current := b.selected, 0
+	b.list.Clear()
+	for i, name := range b.visibleNames() {
+		b.mu.Lock()
+		service, err := b.services[name], b.errs[name]
+		b.mu.Unlock()
+
+		label := tview.Escape(name) + " [gray]…"
+		switch {
+		case err != nil:
+			label = tview.Escape(name) + " [red]!"
+		case service != nil:
+			label = tview.Escape(name) + " " + markText(service)
+		}
+		b.list.AddItem(label, "", 0, nil)
+		if name == selected {
+			current = i
+		}
+	}
+	if b.list.GetItemCount() > 0 {
+		b.list.SetCurrentItem(current)
+	} else {
+		b.selected = ""
+	}
+	b.showService()
+
+	footer := " Tab switch pane  Enter expand  / search  r refresh  q quit"
+	if !b.fetchedAt.IsZero() {
+		footer += fmt.Sprintf("  [gray]updated %s", b.fetchedAt.Format(time.Kitchen))
+	}
+	b.footer.SetText(footer)
+}
+
+func (b *browser) showService() {
+	b.components.Clear()
+	root := b.incidents.GetRoot()
+	root.ClearChildren()
+
+	b.mu.Lock()
+	service, err := b.services[b.selected], b.errs[b.selected]
+	b.mu.Unlock()
+
+	if err != nil {
+		b.components.SetCell(0, 0, tview.NewTableCell(tview.Escape(err.Error())).SetTextColor(tcell.ColorRed))
+		return
+	}
+	if service == nil {
+		b.components.SetCell(0, 0, tview.NewTableCell("Fetching..."))
+		return
+	}
+
+	b.components.SetTitle(fmt.Sprintf(" %s Components ", tview.Escape(strings.Title(service.Name))))
+	for row, c := range service.Components {
+		b.components.SetCell(row, 0, tview.NewTableCell(tview.Escape(c.Name)).SetExpansion(1))
+		b.components.SetCell(row, 1, tview.NewTableCell(statusText(c.Status)))
+	}
+	if len(service.Components) == 0 {
+		b.components.SetCell(0, 0, tview.NewTableCell("No component reports"))
+	}
+
+	incidents := b.matchingIncidents(b.selected)
+	for j := len(incidents) - 1; j >= 0; j-- {
+		i := incidents[j]
+		node := tview.NewTreeNode(fmt.Sprintf("%s  %-8s %s  %s",
+			i.CreatedAt.Format("Jan 2, 2006"),
+			strings.Title(i.Impact),
+			tview.Escape(i.Name),
+			statusText(i.Status),
+		)).SetExpanded(false)
+
+		for _, u := range i.IncidentUpdates {
+			node.AddChild(tview.NewTreeNode(fmt.Sprintf("%s  %s  %s",
+				u.CreatedAt.Format("Jan 2 15:04"),
+				statusText(u.Status),
+				tview.Escape(strings.Join(strings.Fields(u.Body), " ")),
+			)).SetSelectable(false))
+		}
+		root.AddChild(node)
+	}
+	if len(incidents) == 0 {
+		root.AddChild(tview.NewTreeNode("No incident reports").SetSelectable(false))
+	}
+	b.incidents.SetCurrentNode(root.GetChildren()[0])
+}
+
+func statusText(status string) string {
+	s := strings.Title(strings.Replace(status, "_", " ", -1))
+	return tview.TranslateANSI(frain.Render(s))
+}
+
+func markText(s *frain.Service) string {
+	switch mark := frain.Mark(s); mark {
+	case frain.MarkOK:
+		return "[green]" + mark
+	case frain.MarkWarning:
+		return "[yellow]" + mark
+	case frain.MarkCritical:
+		return "[red]" + mark
+	default:
+		return "[gray]" + mark
+	}
+}
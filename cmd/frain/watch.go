@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	watchFlags = flag.NewFlagSet("watch", flag.ExitOnError)
+
+	watchInterval = watchFlags.Duration("interval", time.Minute, "Time to wait between checks")
+	watchOnChange = watchFlags.String("on-change", "", "Command to run whenever a service changes")
+	watchIgnore   = watchFlags.Bool("ignore-maintenance", false, "Do not report or run hooks for planned maintenance")
+	watchTimeout  = watchFlags.Duration("hook-timeout", frain.DefaultHookTimeout, "Time a hook command may run before it is killed")
+)
+
+func init() {
+	register(&command{
+		name:     "watch",
+		args:     "[<service>...]",
+		summary:  "Polls services and reports every change, running hook commands",
+		examples: []string{"frain watch --on-change ./notify.sh github\t==> Run a command whenever github changes"},
+		notes: "Services default to those listed in the configuration file. Hook commands receive\n" +
+			"the change as JSON on stdin and through the FRAIN_SERVICE, FRAIN_COMPONENT,\n" +
+			"FRAIN_INCIDENT, FRAIN_EVENT, FRAIN_OLD_STATUS and FRAIN_NEW_STATUS variables.",
+		flags: watchFlags,
+		run:   runWatch,
+	})
+}
+
+func runWatch(args []string) {
+	cfg := loadConfig()
+	names := args
+	if len(names) == 0 {
+		names = cfg.ServiceNames()
+	}
+	if len(names) == 0 {
+		fmt.Println("frain: no service specified to watch (\"frain watch -h\" for help)")
+		exit()
+	}
+
+	for i, name := range names {
+		resolved, err := resolveService(name)
+		if err != nil {
+			fmt.Println("frain:", err)
+			exit()
+		}
+		names[i] = resolved
+	}
+
+	var hooks []frain.Hook
+	if *watchOnChange != "" {
+		hooks = append(hooks, frain.Hook{Command: *watchOnChange})
+	}
+
+	previous := map[string]*frain.Service{}
+	fmt.Printf("Watching %s every %s\n", strings.Join(names, ", "), *watchInterval)
+
+	for {
+		for _, name := range names {
+			// the full history keeps the recorded snapshots complete
+			service, err := getService(name, historyStart, time.Now())
+			if err != nil {
+				fmt.Printf("%s %s: %v\n", time.Now().Format(time.Stamp), name, err)
+				continue
+			}
+
+			recordSnapshot(service, historyStart, time.Now(), time.Now())
+			if *watchIgnore {
+				service = frain.WithoutMaintenance(service, time.Now())
+			}
+			for _, e := range frain.Changes(previous[name], service) {
+				fmt.Printf("%s %s\n", e.Time.Format(time.Stamp), e)
+				runHooks(append(cfg.Hooks(name), hooks...), e)
+			}
+			previous[name] = service
+		}
+
+		time.Sleep(*watchInterval)
+	}
+}
+
+func runHooks(hooks []frain.Hook, e frain.Event) {
+	for _, h := range hooks {
+		h.Timeout = *watchTimeout
+		out, err := h.Run(e)
+		if len(out) > 0 {
+			os.Stdout.Write(out)
+		}
+		if err != nil {
+			fmt.Println("frain:", err)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package frain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds the settings read from a frain configuration file
+type Config struct {
+	Services []ServiceConfig `json:"services"`
+	SMTP     SMTPConfig      `json:"smtp"`
+	// Aliases maps short names to services, adding to or overriding DefaultAliases
+	Aliases map[string]string `json:"aliases,omitempty"`
+	// Systems maps our own systems to the vendor services they depend on
+	Systems []System `json:"systems,omitempty"`
+}
+
+// ServiceConfig describes a single service listed in the configuration file
+type ServiceConfig struct {
+	Name     string   `json:"name"`
+	OnChange []string `json:"onChange"`
+	// Weight is the importance of the service in the health score, 1 when unset
+	Weight float64 `json:"weight,omitempty"`
+	// CriticalComponents are the components whose trouble counts double in the health
+	// score
+	CriticalComponents []string `json:"criticalComponents,omitempty"`
+	// Feed is the URL of an RSS or Atom incident feed the service is read from instead
+	// of the frain backend
+	Feed string `json:"feed,omitempty"`
+	// Provider is the kind of status page, statusio, instatus or cachet, found at URL
+	// that the service is read from instead of the frain backend
+	Provider string `json:"provider,omitempty"`
+	URL      string `json:"url,omitempty"`
+	// Probes check endpoints of the service ourselves, adding synthetic components
+	Probes []Probe `json:"probes,omitempty"`
+}
+
+// SMTPConfig contains the mail server settings used when sending digests
+type SMTPConfig struct {
+	Addr     string   `json:"addr"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// DefaultConfigPath returns the location frain looks for a configuration file when
+// none is specified
+func DefaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "frain", "config.json")
+}
+
+// LoadConfig reads and decodes the configuration file found at path
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cfg Config
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to decode config file %s: %v", path, err)
+	}
+
+	for i, s := range cfg.Services {
+		cfg.Services[i].Name = strings.ToLower(strings.TrimSpace(s.Name))
+		cfg.Services[i].Feed = strings.TrimSpace(s.Feed)
+		if s.Provider != "" {
+			cfg.Services[i].Provider = strings.ToLower(strings.TrimSpace(s.Provider))
+			if _, err := NewProvider(s.Provider, s.URL); err != nil {
+				return nil, fmt.Errorf("service %s in config file %s: %v", s.Name, path, err)
+			}
+		}
+		for j, p := range s.Probes {
+			p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+			if err := p.validate(); err != nil {
+				return nil, fmt.Errorf("service %s in config file %s: %v", s.Name, path, err)
+			}
+			cfg.Services[i].Probes[j] = p
+		}
+	}
+
+	aliases := map[string]string{}
+	for alias, name := range cfg.Aliases {
+		aliases[strings.ToLower(strings.TrimSpace(alias))] = strings.ToLower(strings.TrimSpace(name))
+	}
+	cfg.Aliases = aliases
+
+	return &cfg, nil
+}
+
+// ServiceNames returns the names of all services listed in the configuration file
+func (c *Config) ServiceNames() []string {
+	var names []string
+	for _, s := range c.Services {
+		if s.Name != "" {
+			names = append(names, s.Name)
+		}
+	}
+
+	return names
+}
+
+// Hooks returns the hooks configured for the named service, whether the configuration
+// file lists it by name or by alias
+func (c *Config) Hooks(name string) []Hook {
+	var hooks []Hook
+	for _, s := range c.matching(name) {
+		for _, cmd := range s.OnChange {
+			hooks = append(hooks, Hook{Command: cmd})
+		}
+	}
+
+	return hooks
+}
+
+// Probes returns the probes configured for the named service, whether the configuration
+// file lists it by name or by alias
+func (c *Config) Probes(name string) []Probe {
+	var probes []Probe
+	for _, s := range c.matching(name) {
+		probes = append(probes, s.Probes...)
+	}
+
+	return probes
+}
+
+// matching returns the configured services standing for the named one
+func (c *Config) matching(name string) []ServiceConfig {
+	aliases := c.ServiceAliases()
+	key := normalizeName(name)
+
+	var services []ServiceConfig
+	for _, s := range c.Services {
+		if n, _ := ResolveService(s.Name, nil, aliases); normalizeName(n) == key {
+			services = append(services, s)
+		}
+	}
+
+	return services
+}
+
+// ServiceAliases returns the built-in aliases merged with those of the configuration
+// file, the latter taking precedence
+func (c *Config) ServiceAliases() map[string]string {
+	aliases := map[string]string{}
+	for alias, name := range DefaultAliases {
+		aliases[alias] = name
+	}
+	for alias, name := range c.Aliases {
+		aliases[alias] = name
+	}
+
+	return aliases
+}
+
+// HealthConfig returns the weights and critical components of the configured services,
+// keyed by the name of the service their alias stands for
+func (c *Config) HealthConfig() HealthConfig {
+	aliases := c.ServiceAliases()
+	cfg := HealthConfig{
+		Weights:            map[string]float64{},
+		CriticalComponents: map[string][]string{},
+	}
+	for _, s := range c.Services {
+		name, _ := ResolveService(s.Name, nil, aliases)
+		if s.Weight > 0 {
+			cfg.Weights[name] = s.Weight
+		}
+		if len(s.CriticalComponents) > 0 {
+			cfg.CriticalComponents[name] = s.CriticalComponents
+		}
+	}
+
+	return cfg
+}
+
+// Provider returns the provider the named service is read from, or nil when it is read
+// from the frain backend
+func (c *Config) Provider(name string) Provider {
+	for _, s := range c.matching(name) {
+		if s.Feed != "" {
+			return FeedProvider{URL: s.Feed}
+		}
+		if p, err := NewProvider(s.Provider, s.URL); err == nil {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// ProvidedServices returns the names of the services read from a provider other than
+// the frain backend
+func (c *Config) ProvidedServices() []string {
+	var names []string
+	for _, s := range c.Services {
+		if s.Name != "" && c.Provider(s.Name) != nil {
+			names = append(names, s.Name)
+		}
+	}
+
+	return names
+}
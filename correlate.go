@@ -0,0 +1,216 @@
+package frain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Discrepancy kinds
+const (
+	// DiscrepancyUnreported is a probe failing or slow while the vendor reports its
+	// component as operational
+	DiscrepancyUnreported = "unreported"
+	// DiscrepancyUnconfirmed is a vendor reporting trouble with a component while the
+	// probe observing it is fine
+	DiscrepancyUnconfirmed = "unconfirmed"
+)
+
+// Discrepancy is a period during which a probe and the status page of the vendor
+// disagreed about a component
+type Discrepancy struct {
+	Service   string `json:"service"`
+	Probe     string `json:"probe"`
+	Component string `json:"component"`
+	Kind      string `json:"kind"`
+	// ProbeStatus and VendorStatus are the worst statuses seen during the period
+	ProbeStatus  ComponentStatus `json:"probeStatus"`
+	VendorStatus ComponentStatus `json:"vendorStatus"`
+	// Since is the first observation of the disagreement and Until when it ended, or
+	// the latest observation while it is Ongoing
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	Ongoing bool      `json:"ongoing"`
+	// AcknowledgedAt is when the vendor admitted to an unreported problem, if it did
+	// before the probe recovered, and AckDelay how long it took
+	AcknowledgedAt *time.Time `json:"acknowledgedAt,omitempty"`
+	AckDelay       Duration   `json:"ackDelay,omitempty"`
+}
+
+// Correlate compares the probes of a service with the status its vendor reported for
+// the components they observe, over snapshots of the service taken the oldest first
+// with the probe results added, and returns the periods of disagreement, the earliest
+// first. Probes without a vendor component are left out.
+//
+// A probe failing or slow is unreported until the component is anything but
+// operational or an unresolved incident names it. The vendor is then taken to have
+// acknowledged the problem at the earliest of the incident creation, the component
+// update and the snapshot, so the delay is only as precise as the snapshots are
+// frequent. A component degraded or down while its probe is operational is unconfirmed.
+func Correlate(service string, probes []Probe, history []*Snapshot) []Discrepancy {
+	var found []Discrepancy
+	for _, p := range probes {
+		if p.Component == "" {
+			continue
+		}
+
+		var open *Discrepancy
+		for _, snap := range history {
+			if snap == nil || snap.Service == nil {
+				continue
+			}
+			probe, vendor, ok := probeComponents(snap.Service, p)
+			if !ok {
+				continue
+			}
+
+			t := snap.TakenAt
+			probeStatus := ParseComponentStatus(probe.Status)
+			vendorStatus := ParseComponentStatus(vendor.Status)
+			probeBad := probeStatus > ComponentOperational
+			reported := vendorStatus > ComponentOperational || incidentNames(snap.Service, p.Component)
+
+			kind := ""
+			switch {
+			case probeBad && !reported:
+				kind = DiscrepancyUnreported
+			case !probeBad && vendorStatus >= ComponentDegradedPerformance:
+				kind = DiscrepancyUnconfirmed
+			}
+
+			if open != nil && open.Kind != kind {
+				open.Until = t
+				if open.Kind == DiscrepancyUnreported && probeBad && reported {
+					ack := acknowledgement(snap.Service, vendor, open.Since, t)
+					open.Until = ack
+					open.AcknowledgedAt = &ack
+					open.AckDelay = Duration(ack.Sub(open.Since))
+				}
+				found = append(found, *open)
+				open = nil
+			}
+			if kind == "" {
+				continue
+			}
+
+			if open == nil {
+				open = &Discrepancy{
+					Service:   service,
+					Probe:     p.ComponentName(),
+					Component: vendor.Name,
+					Kind:      kind,
+					Since:     t,
+				}
+			}
+			open.Until = t
+			if probeStatus > open.ProbeStatus {
+				open.ProbeStatus = probeStatus
+			}
+			if vendorStatus > open.VendorStatus {
+				open.VendorStatus = vendorStatus
+			}
+		}
+
+		if open != nil {
+			open.Ongoing = true
+			found = append(found, *open)
+		}
+	}
+
+	sort.SliceStable(found, func(a, b int) bool { return found[a].Since.Before(found[b].Since) })
+
+	return found
+}
+
+// probeComponents returns the synthetic component of the probe in s and the vendor
+// component it observes
+func probeComponents(s *Service, p Probe) (probe, vendor Component, ok bool) {
+	var hasProbe, hasVendor bool
+	for _, c := range s.Components {
+		switch {
+		case c.IsProbe():
+			if c.ID == "probe:"+p.Target {
+				probe, hasProbe = c, true
+			}
+		case strings.EqualFold(c.Name, p.Component):
+			vendor, hasVendor = c, true
+		}
+	}
+
+	return probe, vendor, hasProbe && hasVendor
+}
+
+// incidentNames reports whether an unresolved incident of s names the component
+func incidentNames(s *Service, component string) bool {
+	for _, i := range s.Incidents {
+		if !ParseIncidentStatus(i.Status).Resolved() && mentions(i, component) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// acknowledgement returns when the vendor acknowledged a problem first observed at
+// since and found acknowledged in a snapshot taken at seen
+func acknowledgement(s *Service, vendor Component, since, seen time.Time) time.Time {
+	ack := seen
+	earlier := func(t time.Time) {
+		if t.After(since) && t.Before(ack) {
+			ack = t
+		}
+	}
+
+	if ParseComponentStatus(vendor.Status) > ComponentOperational {
+		earlier(vendor.UpdatedAt)
+	}
+	for _, i := range s.Incidents {
+		if !ParseIncidentStatus(i.Status).Resolved() && mentions(i, vendor.Name) {
+			earlier(i.CreatedAt)
+		}
+	}
+
+	return ack
+}
+
+// WriteDiscrepancyText writes the discrepancies to w
+func WriteDiscrepancyText(w io.Writer, discrepancies []Discrepancy) error {
+	ew := &errWriter{w: w}
+
+	if len(discrepancies) == 0 {
+		fmt.Fprintln(ew, "No discrepancy between probes and status pages")
+		return ew.err
+	}
+
+	t := &table{header: []string{"SERVICE", "PROBE", "COMPONENT", "FINDING", "SINCE", "FOR", "ACKNOWLEDGED"}}
+	for _, d := range discrepancies {
+		finding := fmt.Sprintf("probe %s, vendor %s", statusWords(d.ProbeStatus), statusWords(d.VendorStatus))
+
+		lasted := d.Until.Sub(d.Since).Round(time.Second).String()
+		if d.Ongoing {
+			lasted = "ongoing"
+		}
+
+		acknowledged := "-"
+		switch {
+		case d.Kind != DiscrepancyUnreported:
+		case d.AcknowledgedAt != nil:
+			acknowledged = "after " + time.Duration(d.AckDelay).Round(time.Second).String()
+		case d.Ongoing:
+			acknowledged = "not yet"
+		default:
+			acknowledged = "never"
+		}
+
+		t.add(title(d.Service), d.Probe, d.Component, finding, maintenanceTime(d.Since), lasted, acknowledged)
+	}
+
+	t.write(ew)
+	return ew.err
+}
+
+func statusWords(s ComponentStatus) string {
+	return strings.Replace(s.String(), "_", " ", -1)
+}
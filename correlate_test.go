@@ -0,0 +1,101 @@
+package frain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func correlationHistory(states ...[2]string) []*Snapshot {
+	start := time.Date(2019, 6, 3, 8, 0, 0, 0, time.UTC)
+
+	var history []*Snapshot
+	for j, st := range states {
+		t := start.Add(time.Duration(j) * 10 * time.Minute)
+		history = append(history, &Snapshot{TakenAt: t, Service: &Service{
+			Name: "github",
+			Components: []Component{
+				{Name: "API Requests", Status: st[1], UpdatedAt: t},
+				{ID: "probe:https://api.github.com", Name: "api.github.com (probe)", Status: st[0]},
+			},
+		}})
+	}
+
+	return history
+}
+
+func TestCorrelateUnreported(t *testing.T) {
+	probes := []Probe{{Type: ProbeHTTP, Target: "https://api.github.com", Component: "api requests"}}
+	history := correlationHistory(
+		[2]string{"operational", "operational"},
+		[2]string{"major_outage", "operational"},
+		[2]string{"degraded_performance", "operational"},
+		[2]string{"major_outage", "partial_outage"},
+		[2]string{"operational", "operational"},
+	)
+
+	found := Correlate("github", probes, history)
+	if len(found) != 1 {
+		t.Fatalf("expected 1 discrepancy, got %+v", found)
+	}
+	d := found[0]
+	if d.Kind != DiscrepancyUnreported || d.ProbeStatus != ComponentMajorOutage || d.VendorStatus != ComponentOperational || d.Ongoing {
+		t.Errorf("unexpected discrepancy %+v", d)
+	}
+	if d.AcknowledgedAt == nil || time.Duration(d.AckDelay) != 20*time.Minute {
+		t.Errorf("expected an acknowledgement after 20 minutes, got %+v", d)
+	}
+
+	// an incident naming the component acknowledges the problem as it is created
+	history[3].Service.Components[0].Status = "operational"
+	history[3].Service.Incidents = []Incident{{
+		Name: "Elevated errors on API requests", Status: "investigating",
+		CreatedAt: history[2].TakenAt.Add(5 * time.Minute),
+	}}
+	found = Correlate("github", probes, history)
+	if len(found) != 1 || time.Duration(found[0].AckDelay) != 15*time.Minute {
+		t.Errorf("expected an acknowledgement by incident after 15 minutes, got %+v", found)
+	}
+}
+
+func TestCorrelateUnconfirmedAndOngoing(t *testing.T) {
+	probes := []Probe{
+		{Type: ProbeHTTP, Target: "https://api.github.com", Component: "API Requests"},
+		{Type: ProbeDNS, Target: "github.com"},
+	}
+	history := correlationHistory(
+		[2]string{"operational", "partial_outage"},
+		[2]string{"operational", "operational"},
+		[2]string{"operational", "under_maintenance"},
+		[2]string{"major_outage", "operational"},
+	)
+
+	found := Correlate("github", probes, history)
+	if len(found) != 2 {
+		t.Fatalf("expected 2 discrepancies, got %+v", found)
+	}
+	if found[0].Kind != DiscrepancyUnconfirmed || found[0].Until.Sub(found[0].Since) != 10*time.Minute {
+		t.Errorf("unexpected discrepancy %+v", found[0])
+	}
+	if found[1].Kind != DiscrepancyUnreported || !found[1].Ongoing || found[1].AcknowledgedAt != nil {
+		t.Errorf("unexpected discrepancy %+v", found[1])
+	}
+
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	var buf bytes.Buffer
+	if err := WriteDiscrepancyText(&buf, found); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{"probe operational, vendor partial outage", "10m0s", "probe major outage, vendor operational", "not yet"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in\n%s", want, out)
+		}
+	}
+}
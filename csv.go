@@ -0,0 +1,127 @@
+package frain
+
+import (
+	"encoding/csv"
+	"io"
+	"time"
+)
+
+// Tables available to the CSV report
+const (
+	TableIncidents  = "incidents"
+	TableComponents = "components"
+)
+
+// The headers only ever grow at the end so that spreadsheets built on them keep working.
+// The description of an incident is the body of its update matching its status, often
+// several lines long, which is what the RFC 4180 quoting is for; it comes last so that
+// the other columns stay put.
+var (
+	incidentHeader  = []string{"service", "id", "name", "impact", "status", "created", "resolved", "duration", "shortlink", "description"}
+	componentHeader = []string{"service", "id", "name", "status", "description", "updated"}
+)
+
+// CSV is a construct to display the page information as comma or tab separated values
+// with one row per incident or component
+type CSV struct {
+	Data *Page
+
+	// Comma is the field delimiter, ',' when unset
+	Comma rune
+	// Table selects the rows written by All, incidents when unset
+	Table string
+}
+
+// Incidents implements the Report interface
+func (c CSV) Incidents(w io.Writer, opts Options) error {
+	return c.writeIncidents(w)
+}
+
+// Components implements the Report interface
+func (c CSV) Components(w io.Writer, opts Options) error {
+	return c.writeComponents(w)
+}
+
+// All implements the Report interface
+func (c CSV) All(w io.Writer, opts Options) error {
+	if c.Table == TableComponents {
+		return c.writeComponents(w)
+	}
+
+	return c.writeIncidents(w)
+}
+
+func (c CSV) writer(out io.Writer) *csv.Writer {
+	w := csv.NewWriter(out)
+	if c.Comma != 0 {
+		w.Comma = c.Comma
+	}
+	// RFC 4180 records end with CRLF
+	w.UseCRLF = w.Comma == ','
+
+	return w
+}
+
+func (c CSV) writeIncidents(out io.Writer) error {
+	w := c.writer(out)
+	w.Write(incidentHeader)
+
+	service := c.Data.Service
+	for _, i := range service.Incidents {
+		resolved, duration := "", ""
+		if ParseIncidentStatus(i.Status).Resolved() {
+			resolvedAt := i.ResolvedAt
+			if resolvedAt.IsZero() {
+				resolvedAt = i.UpdatedAt
+			}
+			resolved = formatTime(resolvedAt)
+			if !resolvedAt.IsZero() && !i.CreatedAt.IsZero() {
+				duration = resolvedAt.Sub(i.CreatedAt).Round(time.Second).String()
+			}
+		}
+
+		w.Write([]string{
+			service.Name,
+			i.ID,
+			i.Name,
+			i.Impact,
+			i.Status,
+			formatTime(i.CreatedAt),
+			resolved,
+			duration,
+			i.Shortlink,
+			incidentDescription(i),
+		})
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func (c CSV) writeComponents(out io.Writer) error {
+	w := c.writer(out)
+	w.Write(componentHeader)
+
+	service := c.Data.Service
+	for _, comp := range service.Components {
+		w.Write([]string{
+			service.Name,
+			comp.ID,
+			comp.Name,
+			comp.Status,
+			comp.Description,
+			formatTime(comp.UpdatedAt),
+		})
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
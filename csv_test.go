@@ -0,0 +1,92 @@
+package frain
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCSVIncidents(t *testing.T) {
+	created := time.Date(2019, 8, 18, 9, 0, 0, 0, time.UTC)
+	page := &Page{
+		Name: "github",
+		Service: &Service{
+			Name: "github",
+			Components: []Component{
+				{ID: "c1", Name: "Webhooks", Status: "partial_outage"},
+			},
+			Incidents: []Incident{
+				{
+					ID:        "i1",
+					Name:      `Webhook "delays"`,
+					Impact:    "minor",
+					Status:    "resolved",
+					Shortlink: "https://stspg.io/x",
+					CreatedAt: created,
+					UpdatedAt: created.Add(90 * time.Minute),
+					IncidentUpdates: []IncidentUpdate{
+						{Status: "resolved", Body: "Fixed.\nAll good now."},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		report CSV
+		want   string
+	}{
+		{
+			CSV{Data: page},
+			"service,id,name,impact,status,created,resolved,duration,shortlink,description\r\n" +
+				`github,i1,"Webhook ""delays""",minor,resolved,2019-08-18T09:00:00Z,2019-08-18T10:30:00Z,1h30m0s,https://stspg.io/x,"Fixed.` + "\r\nAll good now.\"\r\n",
+		},
+		{
+			CSV{Data: page, Comma: '\t', Table: TableComponents},
+			"service\tid\tname\tstatus\tdescription\tupdated\n" +
+				"github\tc1\tWebhooks\tpartial_outage\t\t\n",
+		},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.report.All(&buf, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
+
+func TestCSVUnresolvedIncident(t *testing.T) {
+	created := time.Date(2019, 8, 18, 9, 0, 0, 0, time.UTC)
+	page := &Page{
+		Name: "fastly",
+		Service: &Service{
+			Name: "fastly",
+			Incidents: []Incident{{
+				ID:        "i2",
+				Name:      "Purge delays",
+				Impact:    "major",
+				Status:    "identified",
+				CreatedAt: created,
+				IncidentUpdates: []IncidentUpdate{
+					{Status: "identified", Body: "Purges are slow\tin Europe."},
+					{Status: "investigating", Body: "Looking into it."},
+				},
+			}},
+		},
+	}
+
+	// no resolution time nor duration yet, and the tab in the description is quoted
+	var buf bytes.Buffer
+	if err := (CSV{Data: page, Comma: '\t'}).Incidents(&buf, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	want := "service\tid\tname\timpact\tstatus\tcreated\tresolved\tduration\tshortlink\tdescription\n" +
+		"fastly\ti2\tPurge delays\tmajor\tidentified\t2019-08-18T09:00:00Z\t\t\t\t\"Purges are slow\tin Europe.\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,163 @@
+package frain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ServiceDiff lists what changed in a service between two snapshots
+type ServiceDiff struct {
+	Service string    `json:"service"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+
+	Components []ComponentChange `json:"components"`
+	Appeared   []Incident        `json:"appeared"`
+	Impact     []ImpactChange    `json:"impact"`
+	Resolved   []Incident        `json:"resolved"`
+	Updates    []PostedUpdate    `json:"updates"`
+}
+
+// ComponentChange is a component whose status differs between two snapshots
+type ComponentChange struct {
+	Name      string `json:"name"`
+	OldStatus string `json:"oldStatus"`
+	NewStatus string `json:"newStatus"`
+}
+
+// ImpactChange is an incident whose impact differs between two snapshots
+type ImpactChange struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	OldImpact string `json:"oldImpact"`
+	NewImpact string `json:"newImpact"`
+}
+
+// PostedUpdate is an incident update that was posted between two snapshots
+type PostedUpdate struct {
+	Incident string         `json:"incident"`
+	Update   IncidentUpdate `json:"update"`
+}
+
+// Diff compares two snapshots of the same service, a being the older one
+func Diff(a, b *Snapshot) *ServiceDiff {
+	d := &ServiceDiff{
+		Service: b.Service.Name,
+		From:    a.TakenAt,
+		To:      b.TakenAt,
+	}
+
+	oldComponents := map[string]string{}
+	for _, c := range a.Service.Components {
+		oldComponents[c.Name] = c.Status
+	}
+	for _, c := range b.Service.Components {
+		if status, ok := oldComponents[c.Name]; !ok || status != c.Status {
+			d.Components = append(d.Components, ComponentChange{
+				Name:      c.Name,
+				OldStatus: status,
+				NewStatus: c.Status,
+			})
+		}
+	}
+
+	oldIncidents := map[string]Incident{}
+	for _, i := range a.Service.Incidents {
+		oldIncidents[i.ID] = i
+	}
+	for _, i := range b.Service.Incidents {
+		old, ok := oldIncidents[i.ID]
+		if !ok {
+			d.Appeared = append(d.Appeared, i)
+		} else if old.Impact != i.Impact {
+			d.Impact = append(d.Impact, ImpactChange{
+				ID:        i.ID,
+				Name:      i.Name,
+				OldImpact: old.Impact,
+				NewImpact: i.Impact,
+			})
+		}
+
+		if ParseIncidentStatus(i.Status).Resolved() && (!ok || !ParseIncidentStatus(old.Status).Resolved()) {
+			d.Resolved = append(d.Resolved, i)
+		}
+
+		seen := map[string]bool{}
+		for _, u := range old.IncidentUpdates {
+			seen[u.ID] = true
+		}
+		for _, u := range i.IncidentUpdates {
+			if !seen[u.ID] {
+				d.Updates = append(d.Updates, PostedUpdate{Incident: i.Name, Update: u})
+			}
+		}
+	}
+
+	return d
+}
+
+// Empty reports whether nothing changed between the two snapshots
+func (d *ServiceDiff) Empty() bool {
+	return len(d.Components) == 0 && len(d.Appeared) == 0 && len(d.Impact) == 0 &&
+		len(d.Resolved) == 0 && len(d.Updates) == 0
+}
+
+// WriteText writes a plain text description of the differences to w
+func (d *ServiceDiff) WriteText(w io.Writer) error {
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("Changes to %s between %s and %s\n",
+		d.Service, d.From.Format(time.RFC1123), d.To.Format(time.RFC1123)))
+
+	if d.Empty() {
+		sb.WriteString("\nNothing changed.\n")
+	}
+
+	if len(d.Components) > 0 {
+		sb.WriteString("\nComponents\n")
+		for _, c := range d.Components {
+			sb.WriteString(fmt.Sprintf("  %s: %s -> %s\n", c.Name, orDash(c.OldStatus), orDash(c.NewStatus)))
+		}
+	}
+
+	if len(d.Appeared) > 0 {
+		sb.WriteString("\nNew incidents\n")
+		for _, i := range d.Appeared {
+			sb.WriteString(fmt.Sprintf("  %s (%s, %s impact)\n", i.Name, i.Status, orDash(i.Impact)))
+		}
+	}
+
+	if len(d.Impact) > 0 {
+		sb.WriteString("\nImpact changes\n")
+		for _, i := range d.Impact {
+			sb.WriteString(fmt.Sprintf("  %s: %s -> %s\n", i.Name, orDash(i.OldImpact), orDash(i.NewImpact)))
+		}
+	}
+
+	if len(d.Resolved) > 0 {
+		sb.WriteString("\nResolved incidents\n")
+		for _, i := range d.Resolved {
+			sb.WriteString(fmt.Sprintf("  %s\n", i.Name))
+		}
+	}
+
+	if len(d.Updates) > 0 {
+		sb.WriteString("\nNew updates\n")
+		for _, u := range d.Updates {
+			sb.WriteString(fmt.Sprintf("  %s [%s] %s: %s\n",
+				u.Update.CreatedAt.Format(time.Stamp), u.Update.Status, u.Incident, u.Update.Body))
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,115 @@
+package frain
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	t1 := time.Date(2019, 8, 18, 9, 0, 0, 0, time.UTC)
+	a := &Snapshot{
+		TakenAt: t1,
+		Service: &Service{
+			Name: "github",
+			Components: []Component{
+				{Name: "API Requests", Status: "operational"},
+				{Name: "Webhooks", Status: "degraded_performance"},
+			},
+			Incidents: []Incident{
+				{ID: "1", Name: "Webhook delays", Status: "identified", Impact: "minor",
+					IncidentUpdates: []IncidentUpdate{{ID: "u1", Status: "identified"}}},
+				{ID: "2", Name: "Slow pages", Status: "monitoring", Impact: "minor"},
+			},
+		},
+	}
+	b := &Snapshot{
+		TakenAt: t1.Add(time.Hour),
+		Service: &Service{
+			Name: "github",
+			Components: []Component{
+				{Name: "API Requests", Status: "operational"},
+				{Name: "Webhooks", Status: "major_outage"},
+			},
+			Incidents: []Incident{
+				{ID: "1", Name: "Webhook delays", Status: "identified", Impact: "major",
+					IncidentUpdates: []IncidentUpdate{{ID: "u1", Status: "identified"}, {ID: "u2", Status: "identified"}}},
+				{ID: "2", Name: "Slow pages", Status: "resolved", Impact: "minor"},
+				{ID: "3", Name: "Git operations failing", Status: "investigating", Impact: "critical"},
+			},
+		},
+	}
+
+	d := Diff(a, b)
+	if len(d.Components) != 1 || d.Components[0].NewStatus != "major_outage" {
+		t.Errorf("expected Webhooks component change, got %v", d.Components)
+	}
+	if len(d.Appeared) != 1 || d.Appeared[0].ID != "3" {
+		t.Errorf("expected incident 3 to appear, got %v", d.Appeared)
+	}
+	if len(d.Impact) != 1 || d.Impact[0].OldImpact != "minor" || d.Impact[0].NewImpact != "major" {
+		t.Errorf("expected impact change for incident 1, got %v", d.Impact)
+	}
+	if len(d.Resolved) != 1 || d.Resolved[0].ID != "2" {
+		t.Errorf("expected incident 2 to be resolved, got %v", d.Resolved)
+	}
+	if len(d.Updates) != 1 || d.Updates[0].Update.ID != "u2" {
+		t.Errorf("expected update u2 to be posted, got %v", d.Updates)
+	}
+
+	if d := Diff(a, a); !d.Empty() {
+		t.Errorf("expected no changes between identical snapshots, got %v", d)
+	}
+}
+
+func TestFindSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	t1 := time.Date(2019, 8, 18, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		s := &Service{Name: "github", Status: string(rune('a' + i))}
+		if err := RecordSnapshot(dir, s, t1.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snap, err := FindSnapshot(dir, "github", t1.Add(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Service.Status != "b" {
+		t.Errorf("expected second snapshot, got %v", snap.Service.Status)
+	}
+
+	if _, err := FindSnapshot(dir, "github", t1.Add(-time.Minute)); err == nil {
+		t.Error("expected an error when no snapshot is old enough")
+	}
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	t1 := time.Date(2019, 8, 1, 9, 0, 0, 0, time.UTC)
+	for _, d := range []time.Duration{0, 2 * 24 * time.Hour, 9 * 24 * time.Hour} {
+		if err := RecordSnapshot(dir, &Service{Name: "github"}, t1.Add(d)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snaps, err := SnapshotsSince(dir, "github", t1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 2 || !snaps[0].TakenAt.Equal(t1.Add(2*24*time.Hour)) {
+		t.Errorf("expected the snapshots of the last 8 days only, got %d", len(snaps))
+	}
+}
@@ -0,0 +1,282 @@
+package frain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/smtp"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Digest states of an incident within the reporting window
+const (
+	DigestNew      = "new"
+	DigestOngoing  = "ongoing"
+	DigestResolved = "resolved"
+)
+
+var errNoRecipients = errors.New("Error: no recipients specified for the digest")
+
+// Digest summarises the incidents of several services over a reporting window
+type Digest struct {
+	Since  time.Time
+	Until  time.Time
+	Groups []DigestGroup
+}
+
+// DigestGroup gathers the digest entries sharing the same incident impact
+type DigestGroup struct {
+	Impact  string
+	Entries []DigestEntry
+}
+
+// DigestEntry is a single incident reported in a digest
+type DigestEntry struct {
+	Service  string
+	State    string
+	Incident Incident
+}
+
+// NewDigest builds a digest of the incidents that were new, ongoing or resolved across
+// the given services between since and until
+func NewDigest(services []*Service, since, until time.Time) *Digest {
+	groups := map[string][]DigestEntry{}
+	for _, s := range services {
+		if s == nil {
+			continue
+		}
+
+		for _, i := range s.Incidents {
+			state := digestState(i, since, until)
+			if state == "" {
+				continue
+			}
+
+			impact := strings.ToLower(i.Impact)
+			if impact == "" {
+				impact = "none"
+			}
+			groups[impact] = append(groups[impact], DigestEntry{
+				Service:  s.Name,
+				State:    state,
+				Incident: i,
+			})
+		}
+	}
+
+	d := &Digest{Since: since, Until: until}
+	for _, impact := range orderedImpacts(groups) {
+		entries := groups[impact]
+		sort.SliceStable(entries, func(a, b int) bool {
+			if sa, sb := stateRank(entries[a].State), stateRank(entries[b].State); sa != sb {
+				return sa < sb
+			}
+			if entries[a].Service != entries[b].Service {
+				return entries[a].Service < entries[b].Service
+			}
+			return entries[a].Incident.CreatedAt.After(entries[b].Incident.CreatedAt)
+		})
+		d.Groups = append(d.Groups, DigestGroup{Impact: impact, Entries: entries})
+	}
+
+	return d
+}
+
+// Count returns the number of incidents in the digest with the given state
+func (d *Digest) Count(state string) int {
+	n := 0
+	for _, g := range d.Groups {
+		for _, e := range g.Entries {
+			if e.State == state {
+				n++
+			}
+		}
+	}
+
+	return n
+}
+
+// Subject returns a short subject line suitable for the digest email
+func (d *Digest) Subject() string {
+	return fmt.Sprintf("Frain digest for %s: %d new, %d ongoing, %d resolved",
+		d.Until.Format("Jan 2, 2006"),
+		d.Count(DigestNew),
+		d.Count(DigestOngoing),
+		d.Count(DigestResolved),
+	)
+}
+
+// WriteText writes the plain text version of the digest to w
+func (d *Digest) WriteText(w io.Writer) error {
+	sb := strings.Builder{}
+	sb.WriteString(d.Subject())
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("Incidents between %s and %s\n",
+		d.Since.Format(time.RFC1123), d.Until.Format(time.RFC1123)))
+
+	if len(d.Groups) == 0 {
+		sb.WriteString("\nNo incidents reported.\n")
+	}
+
+	for _, g := range d.Groups {
+		sb.WriteString(fmt.Sprintf("\n%s impact\n", strings.Title(g.Impact)))
+		for _, e := range g.Entries {
+			sb.WriteString(fmt.Sprintf("  [%s] %s: %s (%s)\n",
+				e.State, e.Service, e.Incident.Name, e.Incident.Status))
+			if e.Incident.Shortlink != "" {
+				sb.WriteString(fmt.Sprintf("      %s\n", e.Incident.Shortlink))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var digestHTML = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"title": strings.Title,
+}).Parse(`<html>
+<body>
+<h2>{{.Subject}}</h2>
+<p>Incidents between {{.Since.Format "Mon, 02 Jan 2006 15:04:05 MST"}} and {{.Until.Format "Mon, 02 Jan 2006 15:04:05 MST"}}</p>
+{{- if not .Groups}}
+<p>No incidents reported.</p>
+{{- end}}
+{{- range .Groups}}
+<h3>{{title .Impact}} impact</h3>
+<ul>
+{{- range .Entries}}
+<li><b>[{{.State}}]</b> {{.Service}}: {{if .Incident.Shortlink}}<a href="{{.Incident.Shortlink}}">{{.Incident.Name}}</a>{{else}}{{.Incident.Name}}{{end}} ({{.Incident.Status}})</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the HTML version of the digest to w
+func (d *Digest) WriteHTML(w io.Writer) error {
+	return digestHTML.Execute(w, d)
+}
+
+// Message returns a multipart email containing both the plain text and HTML versions
+// of the digest
+func (d *Digest) Message(from string, to []string) ([]byte, error) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + from,
+		"To: " + strings.Join(to, ", "),
+		"Subject: " + d.Subject(),
+		"Date: " + d.Until.Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		fmt.Sprintf("Content-Type: multipart/alternative; boundary=%q", mw.Boundary()),
+	}
+	buf.WriteString(strings.Join(headers, "\r\n"))
+	buf.WriteString("\r\n\r\n")
+
+	parts := []struct {
+		contentType string
+		write       func(io.Writer) error
+	}{
+		{"text/plain; charset=utf-8", d.WriteText},
+		{"text/html; charset=utf-8", d.WriteHTML},
+	}
+
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if err := p.write(qw); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// SendDigest delivers the digest by email through the SMTP server listening on addr
+func SendDigest(addr string, auth smtp.Auth, from string, to []string, d *Digest) error {
+	if len(to) == 0 {
+		return errNoRecipients
+	}
+
+	msg, err := d.Message(from, to)
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(addr, auth, from, to, msg)
+}
+
+func digestState(i Incident, since, until time.Time) string {
+	if ParseIncidentStatus(i.Status).Resolved() {
+		resolvedAt := i.ResolvedAt
+		if resolvedAt.IsZero() {
+			resolvedAt = i.UpdatedAt
+		}
+		if resolvedAt.Before(since) || resolvedAt.After(until) {
+			return ""
+		}
+		return DigestResolved
+	}
+
+	if i.CreatedAt.After(until) {
+		return ""
+	}
+	if i.CreatedAt.Before(since) {
+		return DigestOngoing
+	}
+
+	return DigestNew
+}
+
+// orderedImpacts returns the impacts of the groups from the most to the least severe,
+// followed by the unknown impacts in alphabetical order
+func orderedImpacts(groups map[string][]DigestEntry) []string {
+	var impacts []string
+	for impact := range groups {
+		impacts = append(impacts, impact)
+	}
+	sort.Slice(impacts, func(a, b int) bool {
+		ia, ib := ParseImpact(impacts[a]), ParseImpact(impacts[b])
+		if ia != ib {
+			return ia > ib
+		}
+		return impacts[a] < impacts[b]
+	})
+
+	return impacts
+}
+
+func stateRank(state string) int {
+	switch state {
+	case DigestNew:
+		return 0
+	case DigestOngoing:
+		return 1
+	}
+
+	return 2
+}
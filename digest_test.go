@@ -0,0 +1,141 @@
+package frain
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func digestServices(now time.Time) []*Service {
+	return []*Service{
+		{
+			Name: "github",
+			Incidents: []Incident{
+				{Name: "Webhook delays", Status: "investigating", Impact: "major", CreatedAt: now.Add(-2 * time.Hour)},
+				{Name: "Pages outage", Status: "resolved", Impact: "critical", CreatedAt: now.Add(-30 * time.Hour), UpdatedAt: now.Add(-3 * time.Hour)},
+				{Name: "Old incident", Status: "resolved", Impact: "minor", CreatedAt: now.Add(-72 * time.Hour), UpdatedAt: now.Add(-48 * time.Hour)},
+			},
+		},
+		{
+			Name: "circleci",
+			Incidents: []Incident{
+				{Name: "Slow builds", Status: "monitoring", Impact: "major", CreatedAt: now.Add(-40 * time.Hour)},
+			},
+		},
+	}
+}
+
+func TestNewDigest(t *testing.T) {
+	now := time.Date(2019, 8, 18, 9, 0, 0, 0, time.UTC)
+	d := NewDigest(digestServices(now), now.Add(-24*time.Hour), now)
+
+	if len(d.Groups) != 2 {
+		t.Fatalf("expected 2 impact groups, got %d", len(d.Groups))
+	}
+
+	tests := []struct {
+		impact string
+		states []string
+	}{
+		{"critical", []string{DigestResolved}},
+		{"major", []string{DigestNew, DigestOngoing}},
+	}
+
+	for i, tt := range tests {
+		g := d.Groups[i]
+		if g.Impact != tt.impact {
+			t.Errorf("expected impact %v, got %v", tt.impact, g.Impact)
+		}
+		if len(g.Entries) != len(tt.states) {
+			t.Fatalf("expected %d entries for %v, got %d", len(tt.states), tt.impact, len(g.Entries))
+		}
+		for j, state := range tt.states {
+			if got := g.Entries[j].State; got != state {
+				t.Errorf("expected state %v, got %v", state, got)
+			}
+		}
+	}
+
+	if want := "Frain digest for Aug 18, 2019: 1 new, 1 ongoing, 1 resolved"; d.Subject() != want {
+		t.Errorf("expected %v, got %v", want, d.Subject())
+	}
+}
+
+func TestSendDigest(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	received := make(chan string, 1)
+	go serveSMTP(l, received)
+
+	now := time.Date(2019, 8, 18, 9, 0, 0, 0, time.UTC)
+	d := NewDigest(digestServices(now), now.Add(-24*time.Hour), now)
+	err = SendDigest(l.Addr().String(), nil, "frain@example.com", []string{"ops@example.com"}, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-received
+	for _, want := range []string{
+		"Subject: Frain digest for Aug 18, 2019",
+		"Content-Type: multipart/alternative",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Type: text/html; charset=utf-8",
+		"Webhook delays",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected message to contain %q", want)
+		}
+	}
+}
+
+// serveSMTP is a minimal SMTP stand-in accepting a single message
+func serveSMTP(l net.Listener, received chan<- string) {
+	conn, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+	reply("220 localhost ESMTP")
+
+	var data strings.Builder
+	inData := false
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		if inData {
+			if line == ".\r\n" {
+				inData = false
+				received <- data.String()
+				reply("250 OK")
+				continue
+			}
+			data.WriteString(line)
+			continue
+		}
+
+		switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case cmd == "DATA":
+			inData = true
+			reply("354 End data with <CR><LF>.<CR><LF>")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
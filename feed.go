@@ -0,0 +1,422 @@
+package frain
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"html"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var errUnknownFeed = errors.New("Error: the feed is neither RSS 2.0 nor Atom")
+
+// FeedProvider reads a service from the RSS 2.0 or Atom incident feed of its status
+// page. Many vendors publish nothing else.
+type FeedProvider struct {
+	URL string
+	// Client fetches the feed, DefaultClient when nil
+	Client *Client
+}
+
+// GetService implements the Provider interface
+func (p FeedProvider) GetService(name string, startTime, endTime time.Time) (*Service, error) {
+	c := p.Client
+	if c == nil {
+		c = DefaultClient
+	}
+
+	resp, err := c.get(p.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	s, err := ParseFeed(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.Name = name
+	s.Incidents = createdBetween(s.Incidents, startTime, endTime)
+
+	return s, nil
+}
+
+type rssFeed struct {
+	Channel struct {
+		Title       string    `xml:"title"`
+		Link        string    `xml:"link"`
+		Description string    `xml:"description"`
+		Items       []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItem struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+}
+
+type atomFeed struct {
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []atomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary"`
+	Content   string     `xml:"content"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+// feedItem is an RSS item or Atom entry, each one an incident or an update of one
+type feedItem struct {
+	ID    string
+	Title string
+	Link  string
+	Body  string
+	Time  time.Time
+}
+
+// ParseFeed reads an RSS 2.0 or Atom incident feed into a service. Items sharing the link
+// of an incident, or else a title once stripped of status markers such as "[Resolved]",
+// are the updates of one incident.
+// Statuses and impacts are guessed from keywords in the titles and descriptions, and the
+// indicator of the service from the worst unresolved incident.
+func ParseFeed(r io.Reader) (*Service, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := rootElement(data)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Service{Provider: root}
+	var items []feedItem
+	switch root {
+	case "rss":
+		var feed rssFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, errJSONDecode
+		}
+		s.StatusPageURL = strings.TrimSpace(feed.Channel.Link)
+		s.Description = strings.TrimSpace(feed.Channel.Description)
+		for _, i := range feed.Channel.Items {
+			id := strings.TrimSpace(i.GUID)
+			if id == "" {
+				id = strings.TrimSpace(i.Link)
+			}
+			items = append(items, feedItem{
+				ID:    id,
+				Title: strings.TrimSpace(i.Title),
+				Link:  strings.TrimSpace(i.Link),
+				Body:  plainText(i.Description),
+				Time:  parseFeedTime(i.PubDate),
+			})
+		}
+
+	case "atom":
+		var feed atomFeed
+		if err := xml.Unmarshal(data, &feed); err != nil {
+			return nil, errJSONDecode
+		}
+		s.StatusPageURL = alternateLink(feed.Links)
+		s.Description = strings.TrimSpace(feed.Subtitle)
+		for _, e := range feed.Entries {
+			body, published := e.Content, e.Published
+			if strings.TrimSpace(body) == "" {
+				body = e.Summary
+			}
+			if published == "" {
+				published = e.Updated
+			}
+			items = append(items, feedItem{
+				ID:    strings.TrimSpace(e.ID),
+				Title: strings.TrimSpace(e.Title),
+				Link:  alternateLink(e.Links),
+				Body:  plainText(body),
+				Time:  parseFeedTime(published),
+			})
+		}
+	}
+
+	s.Incidents = feedIncidents(items, s.StatusPageURL)
+	s.Indicator = IndicatorNone.String()
+	worst := ImpactNone
+	for _, i := range s.Incidents {
+		if impact := ParseImpact(i.Impact); i.IsActive && impact > worst {
+			worst = impact
+		}
+	}
+	if worst > ImpactNone {
+		s.Indicator = worst.String()
+	}
+	if len(s.Incidents) > 0 {
+		s.UpdatedAt = s.Incidents[0].UpdatedAt
+	}
+
+	return s, nil
+}
+
+// rootElement returns "rss" or "atom" depending on the root element of the feed
+func rootElement(data []byte) (string, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return "", errUnknownFeed
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			switch el.Name.Local {
+			case "rss":
+				return "rss", nil
+			case "feed":
+				return "atom", nil
+			}
+			return "", errUnknownFeed
+		}
+	}
+}
+
+func alternateLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+
+	return ""
+}
+
+// feedIncidents groups the items of a feed into incidents, the latest first, each with
+// its updates the latest first. Items go together when they share the incident of a GUID
+// written by WriteFeed or a link to something other than the status page at site, and
+// failing both when they share a title, a resolved item then closing the incident so
+// that a recurring title such as "API outage" starts a new one.
+func feedIncidents(items []feedItem, site string) []Incident {
+	groups := map[string][]feedItem{}
+	var keys []string
+	for _, item := range items {
+		key := incidentKey(item, site)
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	var incidents []Incident
+	for _, key := range keys {
+		for _, group := range splitResolved(key, groups[key]) {
+			incidents = append(incidents, feedIncident(group))
+		}
+	}
+
+	sort.SliceStable(incidents, func(a, b int) bool { return incidents[a].CreatedAt.After(incidents[b].CreatedAt) })
+
+	return incidents
+}
+
+// incidentKey returns what identifies the incident of a feed item, prefixed with "title:"
+// when it is only its title
+func incidentKey(item feedItem, site string) string {
+	if strings.HasPrefix(item.ID, "urn:frain:") {
+		if parts := strings.Split(item.ID, ":"); len(parts) >= 4 {
+			return strings.Join(parts[:4], ":")
+		}
+	}
+	if link := strings.TrimRight(item.Link, "/"); link != "" && link != strings.TrimRight(site, "/") {
+		return "link:" + link
+	}
+
+	return "title:" + strings.ToLower(incidentTitle(item.Title))
+}
+
+// splitResolved splits the items grouped by title into one group per incident, each
+// resolved item ending one
+func splitResolved(key string, items []feedItem) [][]feedItem {
+	if !strings.HasPrefix(key, "title:") {
+		return [][]feedItem{items}
+	}
+
+	sort.SliceStable(items, func(a, b int) bool { return items[a].Time.Before(items[b].Time) })
+
+	var groups [][]feedItem
+	var group []feedItem
+	for _, item := range items {
+		group = append(group, item)
+		if feedStatus(item.Title, item.Body).Resolved() {
+			groups = append(groups, group)
+			group = nil
+		}
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+
+	return groups
+}
+
+// feedIncident makes an incident of the items of a group
+func feedIncident(group []feedItem) Incident {
+	sort.SliceStable(group, func(a, b int) bool { return group[a].Time.After(group[b].Time) })
+	first, last := group[len(group)-1], group[0]
+
+	i := Incident{
+		ID:         first.ID,
+		IncidentID: first.ID,
+		Name:       incidentTitle(first.Title),
+		Shortlink:  last.Link,
+		CreatedAt:  first.Time,
+		UpdatedAt:  last.Time,
+	}
+
+	var text []string
+	for _, item := range group {
+		status := feedStatus(item.Title, item.Body)
+		i.IncidentUpdates = append(i.IncidentUpdates, IncidentUpdate{
+			ID:               item.ID,
+			IncidentUpdateID: item.ID,
+			IncidentID:       i.IncidentID,
+			Status:           status.String(),
+			Body:             item.Body,
+			CreatedAt:        item.Time,
+			UpdatedAt:        item.Time,
+		})
+		text = append(text, item.Title, item.Body)
+	}
+
+	status := ParseIncidentStatus(i.IncidentUpdates[0].Status)
+	i.Status = status.String()
+	i.IsActive = !status.Resolved()
+	if !i.IsActive {
+		i.ResolvedAt = last.Time
+	}
+	i.Impact = feedImpact(strings.Join(text, " ")).String()
+
+	return i
+}
+
+var (
+	statusMarker = regexp.MustCompile(`(?i)^\s*(\[[^\]]*\]|\((resolved|investigating|identified|monitoring|update|updated|completed|scheduled|in progress)\)|(resolved|investigating|identified|monitoring|update|updated|completed|scheduled|in progress)\s*[:\-–])\s*`)
+	htmlBreak    = regexp.MustCompile(`(?i)<(br|/?p|/?div|/?li|/?h[1-6])\b[^>]*>`)
+	htmlTag      = regexp.MustCompile(`<[^>]*>`)
+)
+
+// incidentTitle strips status markers such as "[Resolved]" or "Update:" from the title
+// of a feed item
+func incidentTitle(title string) string {
+	for {
+		stripped := statusMarker.ReplaceAllString(title, "")
+		if stripped == title || stripped == "" {
+			return strings.TrimSpace(title)
+		}
+		title = stripped
+	}
+}
+
+// feedStatuses maps the keywords of feed items to incident statuses
+var feedStatuses = []struct {
+	status IncidentStatus
+	re     *regexp.Regexp
+}{
+	{IncidentResolved, regexp.MustCompile(`(?i)\b(resolved|fixed|restored|recovered|back to normal|operating normally)\b`)},
+	{IncidentMonitoring, regexp.MustCompile(`(?i)\bmonitoring\b`)},
+	{IncidentIdentified, regexp.MustCompile(`(?i)\b(identified|root cause)\b`)},
+	{IncidentInvestigating, regexp.MustCompile(`(?i)\b(investigating|looking into|aware of)\b`)},
+	{IncidentCompleted, regexp.MustCompile(`(?i)\bcompleted\b`)},
+	{IncidentInProgress, regexp.MustCompile(`(?i)\b(in progress|underway|under way)\b`)},
+	{IncidentScheduled, regexp.MustCompile(`(?i)\b(scheduled|planned)\b`)},
+}
+
+// feedStatus guesses the status of a feed item from the keyword coming first in the
+// status marker of its title, or else in its body, or else in its title. Status pages
+// write the latest update first, so a body holding the whole history of an incident
+// gives its current status. Items without a keyword are taken for an investigation.
+func feedStatus(title, body string) IncidentStatus {
+	for _, text := range []string{statusMarker.FindString(title), body, title} {
+		status, at := IncidentUnknown, len(text)
+		for _, s := range feedStatuses {
+			if loc := s.re.FindStringIndex(text); loc != nil && loc[0] < at {
+				status, at = s.status, loc[0]
+			}
+		}
+		if status != IncidentUnknown {
+			return status
+		}
+	}
+
+	return IncidentInvestigating
+}
+
+// feedImpacts maps the keywords of feed items to impacts, the most severe first
+var feedImpacts = []struct {
+	impact Impact
+	re     *regexp.Regexp
+}{
+	{ImpactMaintenance, regexp.MustCompile(`(?i)\bmaintenance\b`)},
+	{ImpactCritical, regexp.MustCompile(`(?i)\b(major outage|complete outage|full outage|all services)\b`)},
+	{ImpactMajor, regexp.MustCompile(`(?i)\b(outage|unavailable|down|not working|failing|failures?)\b`)},
+	{ImpactMinor, regexp.MustCompile(`(?i)\b(degraded|delays?|delayed|slow|slowness|elevated|intermittent|increased)\b`)},
+}
+
+// feedImpact guesses the impact of an incident from the text of its items. A mention of
+// maintenance wins, otherwise the most severe keyword does and an incident without any
+// is taken for a minor one.
+func feedImpact(text string) Impact {
+	for _, i := range feedImpacts {
+		if i.re.MatchString(text) {
+			return i.impact
+		}
+	}
+
+	return ImpactMinor
+}
+
+// plainText turns the HTML description of a feed item into a single line of text
+func plainText(s string) string {
+	s = htmlTag.ReplaceAllString(htmlBreak.ReplaceAllString(s, " "), "")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// parseFeedTime reads the RFC 822 dates of RSS and the RFC 3339 dates of Atom, returning
+// the zero time for anything else
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,191 @@
+package frain
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseFeedFile(t *testing.T, name string) *Service {
+	f, err := os.Open(filepath.Join("testdata", "feeds", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	s, err := ParseFeed(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func TestParseRSSFeed(t *testing.T) {
+	s := parseFeedFile(t, "mailgun.rss")
+
+	if s.Provider != "rss" || s.StatusPageURL != "https://status.mailgun.com" || s.Indicator != "minor" {
+		t.Errorf("unexpected service %+v", s)
+	}
+
+	want := []struct{ name, status, impact string }{
+		{"Delayed email deliveries in the EU region", "monitoring", "minor"},
+		{"API outage", "resolved", "major"},
+		{"Scheduled database maintenance", "completed", "maintenance"},
+	}
+	if len(s.Incidents) != len(want) {
+		t.Fatalf("expected %d incidents, got %+v", len(want), s.Incidents)
+	}
+	for j, w := range want {
+		i := s.Incidents[j]
+		if i.Name != w.name || i.Status != w.status || i.Impact != w.impact {
+			t.Errorf("expected %+v, got %s, %s and %s", w, i.Name, i.Status, i.Impact)
+		}
+	}
+
+	i := s.Incidents[0]
+	if i.IncidentID != "https://status.mailgun.com/incidents/mg1" || !i.IsActive {
+		t.Errorf("unexpected incident %+v", i)
+	}
+	if body := i.IncidentUpdates[0].Body; !strings.HasPrefix(body, "Jun 3, 10:15 UTC Monitoring - A fix") {
+		t.Errorf("expected the description as plain text, got %q", body)
+	}
+	if !i.CreatedAt.Equal(time.Date(2019, 6, 3, 8, 40, 0, 0, time.UTC)) {
+		t.Errorf("unexpected creation time %v", i.CreatedAt)
+	}
+}
+
+func TestParseAtomFeed(t *testing.T) {
+	s := parseFeedFile(t, "medium.atom")
+
+	if s.Provider != "atom" || s.StatusPageURL != "https://medium.statuspage.example" || s.Indicator != "minor" {
+		t.Errorf("unexpected service %+v", s)
+	}
+	if len(s.Incidents) != 2 {
+		t.Fatalf("expected the entries to be grouped into 2 incidents, got %+v", s.Incidents)
+	}
+
+	slow := s.Incidents[0]
+	if slow.Name != "Slow image uploads" || slow.Status != "investigating" || slow.Impact != "minor" {
+		t.Errorf("unexpected incident %+v", slow)
+	}
+
+	stories := s.Incidents[1]
+	if stories.Name != "Stories fail to load for some readers" || stories.Status != "resolved" || stories.IsActive {
+		t.Errorf("unexpected incident %+v", stories)
+	}
+	statuses := []string{"resolved", "identified", "investigating"}
+	for j, u := range stories.IncidentUpdates {
+		if u.Status != statuses[j] || u.IncidentID != stories.IncidentID {
+			t.Errorf("update %d: expected %s, got %+v", j, statuses[j], u)
+		}
+	}
+	if stories.IncidentID != "tag:medium.statuspage.example,2019:update/md1-1" {
+		t.Errorf("expected the first entry to identify the incident, got %s", stories.IncidentID)
+	}
+	if !stories.ResolvedAt.Equal(time.Date(2019, 6, 2, 17, 20, 0, 0, time.UTC)) {
+		t.Errorf("unexpected resolution time %v", stories.ResolvedAt)
+	}
+}
+
+func TestParseFeedErrors(t *testing.T) {
+	for _, feed := range []string{"", "not xml", `<?xml version="1.0"?><html></html>`} {
+		if _, err := ParseFeed(strings.NewReader(feed)); err == nil {
+			t.Errorf("%q: expected an error", feed)
+		}
+	}
+}
+
+func TestFeedRecurringTitles(t *testing.T) {
+	feed := `<?xml version="1.0"?>
+<rss version="2.0"><channel>
+  <title>Example status</title>
+  <link>https://status.example.com</link>
+  <item><title>API outage</title><description>The API is down.</description>
+    <pubDate>Mon, 04 Mar 2019 10:00:00 +0000</pubDate><link>https://status.example.com/incidents/a1</link></item>
+  <item><title>API outage</title><description>Resolved - the API is back.</description>
+    <pubDate>Mon, 04 Mar 2019 11:00:00 +0000</pubDate><link>https://status.example.com/incidents/a1</link></item>
+  <item><title>API outage</title><description>The API is down.</description>
+    <pubDate>Mon, 03 Jun 2019 10:00:00 +0000</pubDate><link>https://status.example.com/incidents/a2</link></item>
+  <item><title>Login issues</title><description>Resolved - logins work.</description>
+    <pubDate>Tue, 05 Mar 2019 11:00:00 +0000</pubDate><link>https://status.example.com</link></item>
+  <item><title>Login issues</title><description>Logins fail.</description>
+    <pubDate>Tue, 04 Jun 2019 10:00:00 +0000</pubDate><link>https://status.example.com</link></item>
+</channel></rss>`
+
+	s, err := ParseFeed(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name    string
+		status  string
+		updates int
+	}{
+		{"Login issues", "investigating", 1},
+		{"API outage", "investigating", 1},
+		{"Login issues", "resolved", 1},
+		{"API outage", "resolved", 2},
+	}
+	if len(s.Incidents) != len(want) {
+		t.Fatalf("expected %d incidents, got %+v", len(want), s.Incidents)
+	}
+	for j, w := range want {
+		i := s.Incidents[j]
+		if i.Name != w.name || i.Status != w.status || len(i.IncidentUpdates) != w.updates {
+			t.Errorf("expected %+v, got %s, %s with %d updates", w, i.Name, i.Status, len(i.IncidentUpdates))
+		}
+	}
+}
+
+func TestFeedStatusAndImpact(t *testing.T) {
+	statuses := map[[2]string]IncidentStatus{
+		{"[Resolved] Login issues", "We are investigating."}:             IncidentResolved,
+		{"Login issues", "Resolved - all good. Investigating - logins."}: IncidentResolved,
+		{"Scheduled maintenance", "The maintenance is now in progress."}: IncidentInProgress,
+		{"Login issues", "Nothing to add."}:                              IncidentInvestigating,
+	}
+	for in, want := range statuses {
+		if got := feedStatus(in[0], in[1]); got != want {
+			t.Errorf("%q: expected %v, got %v", in, want, got)
+		}
+	}
+
+	impacts := map[string]Impact{
+		"Major outage of all regions":        ImpactCritical,
+		"The dashboard is down":              ImpactMajor,
+		"Webhook deliveries are delayed":     ImpactMinor,
+		"Planned maintenance of the network": ImpactMaintenance,
+		"Something is off":                   ImpactMinor,
+	}
+	for text, want := range impacts {
+		if got := feedImpact(text); got != want {
+			t.Errorf("%q: expected %v, got %v", text, want, got)
+		}
+	}
+}
+
+func TestFeedProvider(t *testing.T) {
+	srv := httptest.NewServer(http.FileServer(http.Dir(filepath.Join("testdata", "feeds"))))
+	defer srv.Close()
+
+	c, _ := testClient(srv.URL, 0)
+	p := FeedProvider{URL: srv.URL + "/mailgun.rss", Client: c}
+	s, err := p.GetService("mailgun", time.Date(2019, 5, 20, 0, 0, 0, 0, time.UTC), time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "mailgun" || len(s.Incidents) != 2 {
+		t.Errorf("expected the incidents of May 20 to June 3, got %+v", s.Incidents)
+	}
+
+	p.URL = srv.URL + "/missing.rss"
+	if _, err := p.GetService("mailgun", time.Time{}, time.Now()); err == nil {
+		t.Error("expected an error for a missing feed")
+	}
+}
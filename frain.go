@@ -39,6 +39,7 @@ type Service struct {
 	Components          []Component     `json:"components"`
 	Incidents           []Incident      `json:"incidents"`
 	HighLevelComponents []SubComponents `json:"highLevelComponents"`
+	Maintenances        []Maintenance   `json:"scheduledMaintenances"`
 }
 
 // Component contains information about a service's components
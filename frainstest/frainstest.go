@@ -0,0 +1,266 @@
+// Package frainstest provides a fake frain backend for tests and offline demos. It
+// answers the getService and getAllServices GraphQL queries sent by frain from services
+// loaded from fixture files, and can be scripted to fail, stall or return malformed
+// JSON.
+package frainstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+var (
+	serviceQuery = regexp.MustCompile(`getService\s*\(\s*name\s*:\s*"?([^")\s]+)"?\s*\)`)
+	startQuery   = regexp.MustCompile(`startTime\s*:\s*"([0-9-]+)"`)
+	endQuery     = regexp.MustCompile(`endTime\s*:\s*"([0-9-]+)"`)
+)
+
+// Fault describes how the backend misbehaves for a single request. The zero Fault
+// answers normally.
+type Fault struct {
+	// Status is the HTTP status code returned instead of an answer, if set
+	Status int
+	// Header is added to the response, e.g. Retry-After
+	Header http.Header
+	// Delay holds the response back
+	Delay time.Duration
+	// Malformed truncates the JSON of the answer
+	Malformed bool
+}
+
+// Backend is an http.Handler speaking the frain GraphQL schema
+type Backend struct {
+	mu       sync.Mutex
+	services map[string]*frain.Service
+	faults   []Fault
+	delay    time.Duration
+	requests int
+}
+
+// NewBackend returns a backend serving the given services
+func NewBackend(services ...*frain.Service) *Backend {
+	b := &Backend{services: map[string]*frain.Service{}}
+	b.Add(services...)
+
+	return b
+}
+
+// Add makes services available, replacing any service with the same name
+func (b *Backend) Add(services ...*frain.Service) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range services {
+		b.services[strings.ToLower(s.Name)] = s
+	}
+}
+
+// Script queues faults to be applied to the next requests, one fault per request
+func (b *Backend) Script(faults ...Fault) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.faults = append(b.faults, faults...)
+}
+
+// SetDelay holds back every response by d, on top of any scripted delay
+func (b *Backend) SetDelay(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.delay = d
+}
+
+// Requests returns the number of requests received so far
+func (b *Backend) Requests() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.requests
+}
+
+func (b *Backend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	b.requests++
+	var fault Fault
+	if len(b.faults) > 0 {
+		fault, b.faults = b.faults[0], b.faults[1:]
+	}
+	delay := b.delay + fault.Delay
+	b.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	for k, v := range fault.Header {
+		w.Header()[k] = v
+	}
+	if fault.Status != 0 {
+		writeError(w, fault.Status, http.StatusText(fault.Status))
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "frain queries must be sent with POST")
+		return
+	}
+
+	var req struct {
+		Query string `json:"query"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("malformed request: %v", err))
+		return
+	}
+
+	data, err := b.answer(req.Query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"data": data})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if fault.Malformed {
+		body = body[:len(body)/2]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// answer resolves a query to the value of the data field of the response
+func (b *Backend) answer(query string) (interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if strings.Contains(query, "getAllServices") {
+		var names []string
+		for name := range b.services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		all := []map[string]string{}
+		for _, name := range names {
+			all = append(all, map[string]string{"name": b.services[name].Name})
+		}
+		return map[string]interface{}{"getAllServices": all}, nil
+	}
+
+	m := serviceQuery.FindStringSubmatch(query)
+	if m == nil {
+		return nil, fmt.Errorf("unsupported query %q", query)
+	}
+
+	s, ok := b.services[strings.ToLower(m[1])]
+	if !ok {
+		return map[string]interface{}{"getService": nil}, nil
+	}
+
+	return map[string]interface{}{"getService": between(s, dateArg(startQuery, query), dateArg(endQuery, query))}, nil
+}
+
+// between returns a copy of s holding only the incidents created from the start day up
+// to and including the end day. A zero time leaves that side open.
+func between(s *frain.Service, start, end time.Time) *frain.Service {
+	c := *s
+	c.Incidents = []frain.Incident{}
+	for _, i := range s.Incidents {
+		if !start.IsZero() && i.CreatedAt.Before(start) {
+			continue
+		}
+		if !end.IsZero() && !i.CreatedAt.Before(end.AddDate(0, 0, 1)) {
+			continue
+		}
+		c.Incidents = append(c.Incidents, i)
+	}
+
+	return &c
+}
+
+func dateArg(re *regexp.Regexp, query string) time.Time {
+	m := re.FindStringSubmatch(query)
+	if m == nil {
+		return time.Time{}
+	}
+	t, _ := time.Parse("2006-01-02", m[1])
+
+	return t
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"errors": []map[string]string{{"message": msg}},
+	})
+}
+
+// Server is a Backend listening on a local port, as started by NewServer
+type Server struct {
+	*Backend
+	*httptest.Server
+}
+
+// NewServer starts a fake backend serving the given services. Point a frain.Client or
+// the FRAIN_HOST environment variable at its URL and Close it when done.
+func NewServer(services ...*frain.Service) *Server {
+	b := NewBackend(services...)
+
+	return &Server{Backend: b, Server: httptest.NewServer(b)}
+}
+
+// LoadFixtures reads every JSON file in dir as a service in the form returned by the
+// getService query. Services without a name are named after their file.
+func LoadFixtures(dir string) ([]*frain.Service, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		if _, err := os.Stat(dir); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("no fixture found in %s", dir)
+	}
+
+	var services []*frain.Service
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var s frain.Service
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("failed to decode fixture %s: %v", path, err)
+		}
+		if s.Name == "" {
+			s.Name = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		services = append(services, &s)
+	}
+
+	return services, nil
+}
@@ -0,0 +1,117 @@
+package frainstest
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mekilis/frain"
+)
+
+func fixtureServer(t *testing.T) *Server {
+	services, err := LoadFixtures("fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewServer(services...)
+}
+
+func testClient(url string, retries int) *frain.Client {
+	return &frain.Client{
+		Host:       url,
+		HTTPClient: &http.Client{Timeout: time.Second},
+		Retry:      frain.RetryPolicy{Retries: retries, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+}
+
+func TestGetService(t *testing.T) {
+	srv := fixtureServer(t)
+	defer srv.Close()
+	c := testClient(srv.URL, 0)
+
+	s, err := c.GetService("github", time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "github" || len(s.Components) != 4 || len(s.Incidents) != 2 {
+		t.Errorf("unexpected service %+v", s)
+	}
+	if s.Incidents[0].IncidentUpdates[0].Status != "monitoring" {
+		t.Errorf("expected the latest update first, got %+v", s.Incidents[0].IncidentUpdates)
+	}
+	if len(s.Maintenances) != 2 || s.Maintenances[0].ScheduledFor.IsZero() {
+		t.Errorf("unexpected maintenances %+v", s.Maintenances)
+	}
+
+	// only the incident of 2019-06-03 falls within the range
+	s, err = c.GetService("github", time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Incidents) != 1 || s.Incidents[0].ID != "i1" {
+		t.Errorf("expected incident i1 only, got %+v", s.Incidents)
+	}
+
+	s, err = c.GetService("unknown", time.Time{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Name != "" {
+		t.Errorf("expected no service, got %+v", s)
+	}
+}
+
+func TestGetServiceList(t *testing.T) {
+	srv := fixtureServer(t)
+	defer srv.Close()
+
+	sl, err := testClient(srv.URL, 0).GetServiceList()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(sl)
+
+	want := []string{"circleci", "datadog", "fastly", "github"}
+	if !reflect.DeepEqual(sl, want) {
+		t.Errorf("expected %v, got %v", want, sl)
+	}
+}
+
+func TestScriptedFaults(t *testing.T) {
+	srv := NewServer(&frain.Service{Name: "github"})
+	defer srv.Close()
+
+	srv.Script(Fault{Status: http.StatusServiceUnavailable}, Fault{Status: http.StatusBadGateway})
+	if _, err := testClient(srv.URL, 2).GetServiceList(); err != nil {
+		t.Errorf("expected the client to retry past the failures, got %v", err)
+	}
+	if n := srv.Requests(); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+
+	srv.Script(Fault{Status: http.StatusInternalServerError})
+	if _, err := testClient(srv.URL, 2).GetServiceList(); err == nil {
+		t.Error("expected an error for a 500 response")
+	}
+
+	srv.Script(Fault{Malformed: true})
+	if _, err := testClient(srv.URL, 0).GetServiceList(); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+
+	srv.Script(Fault{Delay: time.Second})
+	c := testClient(srv.URL, 0)
+	c.HTTPClient.Timeout = 100 * time.Millisecond
+	if _, err := c.GetServiceList(); err == nil {
+		t.Error("expected the request to time out")
+	}
+}
+
+func TestLoadFixturesMissing(t *testing.T) {
+	if _, err := LoadFixtures("does-not-exist"); err == nil {
+		t.Error("expected an error for a missing fixture directory")
+	}
+}
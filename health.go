@@ -0,0 +1,305 @@
+package frain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// HealthLevel is the overall verdict on the health of one or more services
+type HealthLevel int
+
+// Health levels from the best to the worst. Unknown is used when no service could be
+// assessed.
+const (
+	HealthOK HealthLevel = iota
+	HealthWarning
+	HealthCritical
+	HealthUnknown
+)
+
+var healthLevels = []string{
+	HealthOK:       "ok",
+	HealthWarning:  "warning",
+	HealthCritical: "critical",
+	HealthUnknown:  "unknown",
+}
+
+var healthColours = map[HealthLevel]*color.Color{
+	HealthOK:       color.New(color.FgGreen),
+	HealthWarning:  color.New(color.FgYellow),
+	HealthCritical: color.New(color.FgRed),
+	HealthUnknown:  color.New(color.FgWhite),
+}
+
+func (l HealthLevel) String() string {
+	return enumString(int(l), healthLevels)
+}
+
+// MarshalText encodes the level by name, e.g. in JSON output
+func (l HealthLevel) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// Scores from which a service is considered healthy or merely degraded
+const (
+	healthyScore  = 90
+	degradedScore = 50
+)
+
+// Penalties taken off the score of a service, out of 100
+var (
+	indicatorPenalties = map[Indicator]int{
+		IndicatorMaintenance: 10,
+		IndicatorMinor:       25,
+		IndicatorMajor:       60,
+		IndicatorCritical:    100,
+	}
+	componentPenalties = map[ComponentStatus]int{
+		ComponentUnderMaintenance:    5,
+		ComponentDegradedPerformance: 20,
+		ComponentPartialOutage:       40,
+		ComponentMajorOutage:         80,
+	}
+	impactPenalties = map[Impact]int{
+		ImpactNone:        5,
+		ImpactMaintenance: 5,
+		ImpactMinor:       20,
+		ImpactMajor:       50,
+		ImpactCritical:    90,
+	}
+)
+
+// HealthConfig tunes how services are combined into a health score
+type HealthConfig struct {
+	// Weights gives the relative importance of services by name, 1 when missing
+	Weights map[string]float64
+	// CriticalComponents lists by service the components whose trouble counts double
+	CriticalComponents map[string][]string
+}
+
+// HealthReason is something dragging the score of a service down
+type HealthReason struct {
+	Service string `json:"service"`
+	Penalty int    `json:"penalty"`
+	Text    string `json:"text"`
+}
+
+// ServiceHealth is the health of a single service
+type ServiceHealth struct {
+	Service string         `json:"service"`
+	Score   int            `json:"score"`
+	Level   HealthLevel    `json:"level"`
+	Weight  float64        `json:"weight"`
+	Reasons []HealthReason `json:"reasons,omitempty"`
+}
+
+// Health is the combined health of a set of services
+type Health struct {
+	Score    int             `json:"score"`
+	Level    HealthLevel     `json:"level"`
+	Services []ServiceHealth `json:"services"`
+}
+
+// AssessHealth scores every service out of 100 from its indicator, the status of its
+// components and the impact of its unresolved incidents, the worst of them setting the
+// score. The overall score is the weighted average of the service scores and the overall
+// level is the worst level of a service once a weight below 1 scales down how far its
+// score falls short of 100, so that a service weighing 0.5 or less is a warning at
+// worst. A nil entry in services stands for a service that could not be fetched; it
+// lowers the level to warning at best. names holds the name of every entry of services.
+func AssessHealth(names []string, services []*Service, cfg HealthConfig) *Health {
+	h := &Health{Level: HealthOK}
+
+	var total, weights float64
+	assessed := false
+	for i, s := range services {
+		name := names[i]
+		weight := 1.0
+		if w, ok := cfg.Weights[strings.ToLower(name)]; ok {
+			weight = w
+		}
+
+		if s == nil {
+			h.Services = append(h.Services, ServiceHealth{
+				Service: name,
+				Level:   HealthUnknown,
+				Weight:  weight,
+				Reasons: []HealthReason{{Service: name, Text: "could not be fetched"}},
+			})
+			// not knowing is not OK
+			if weight > 0 && h.Level < HealthWarning {
+				h.Level = HealthWarning
+			}
+			continue
+		}
+
+		sh := assessService(name, s, cfg.CriticalComponents[strings.ToLower(name)])
+		sh.Weight = weight
+		h.Services = append(h.Services, sh)
+
+		total += weight * float64(sh.Score)
+		weights += weight
+		assessed = true
+		if level := scoreLevel(weightedScore(sh.Score, weight)); level > h.Level {
+			h.Level = level
+		}
+	}
+
+	if !assessed {
+		h.Level = HealthUnknown
+	}
+	if weights > 0 {
+		h.Score = int(total/weights + 0.5)
+	}
+
+	return h
+}
+
+func assessService(name string, s *Service, critical []string) ServiceHealth {
+	sh := ServiceHealth{Service: name}
+
+	if p := indicatorPenalties[ParseIndicator(s.Indicator)]; p > 0 {
+		sh.Reasons = append(sh.Reasons, HealthReason{
+			Service: name,
+			Penalty: p,
+			Text:    fmt.Sprintf("status page reports a %s problem", s.Indicator),
+		})
+	}
+
+	for _, c := range s.Components {
+		status := ParseComponentStatus(c.Status)
+		p := componentPenalties[status]
+		if p == 0 {
+			continue
+		}
+
+		text := fmt.Sprintf("component %s is %s", c.Name, strings.Replace(status.String(), "_", " ", -1))
+		if isCritical(c.Name, critical) {
+			p *= 2
+			text = "critical " + text
+		}
+		if p > 100 {
+			p = 100
+		}
+		sh.Reasons = append(sh.Reasons, HealthReason{Service: name, Penalty: p, Text: text})
+	}
+
+	for _, i := range s.Incidents {
+		if ParseIncidentStatus(i.Status).Resolved() {
+			continue
+		}
+
+		impact := ParseImpact(i.Impact)
+		p, ok := impactPenalties[impact]
+		if !ok {
+			p = impactPenalties[ImpactMinor]
+		}
+		sh.Reasons = append(sh.Reasons, HealthReason{
+			Service: name,
+			Penalty: p,
+			Text:    fmt.Sprintf("%s incident %q is %s", impact, i.Name, strings.ToLower(i.Status)),
+		})
+	}
+
+	sort.SliceStable(sh.Reasons, func(a, b int) bool { return sh.Reasons[a].Penalty > sh.Reasons[b].Penalty })
+
+	sh.Score = 100
+	if len(sh.Reasons) > 0 {
+		sh.Score -= sh.Reasons[0].Penalty
+	}
+	sh.Level = scoreLevel(sh.Score)
+
+	return sh
+}
+
+// weightedScore scales how far score falls short of 100 by a weight below 1
+func weightedScore(score int, weight float64) int {
+	if weight >= 1 {
+		return score
+	}
+	if weight <= 0 {
+		return 100
+	}
+
+	return 100 - int(float64(100-score)*weight+0.5)
+}
+
+func isCritical(component string, critical []string) bool {
+	for _, c := range critical {
+		if strings.EqualFold(c, component) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func scoreLevel(score int) HealthLevel {
+	switch {
+	case score >= healthyScore:
+		return HealthOK
+	case score >= degradedScore:
+		return HealthWarning
+	}
+
+	return HealthCritical
+}
+
+// Reasons returns what drags the overall score down, the heaviest first. Reasons are
+// weighed by the weight of their service.
+func (h *Health) Reasons() []HealthReason {
+	var reasons []HealthReason
+	weight := map[string]float64{}
+	for _, s := range h.Services {
+		weight[s.Service] = s.Weight
+		reasons = append(reasons, s.Reasons...)
+	}
+
+	sort.SliceStable(reasons, func(a, b int) bool {
+		wa := weight[reasons[a].Service] * float64(reasons[a].Penalty)
+		wb := weight[reasons[b].Service] * float64(reasons[b].Penalty)
+		return wa > wb
+	})
+
+	return reasons
+}
+
+// WriteText writes the overall verdict, the score of every service and up to max
+// reasons dragging the score down. A max of 0 writes every reason.
+func (h *Health) WriteText(w io.Writer, max int) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintf(ew, "Health: %s (score %d/100)\n\n", healthColours[h.Level].Sprint(h.Level), h.Score)
+
+	t := &table{header: []string{"SERVICE", "SCORE", "LEVEL"}}
+	for _, s := range h.Services {
+		score := fmt.Sprint(s.Score)
+		if s.Level == HealthUnknown {
+			score = "-"
+		}
+		t.add(title(s.Service), score, healthColours[s.Level].Sprint(s.Level))
+	}
+	t.write(ew)
+
+	reasons := h.Reasons()
+	if len(reasons) == 0 {
+		return ew.err
+	}
+	if max > 0 && len(reasons) > max {
+		reasons = reasons[:max]
+	}
+
+	bold.Fprintln(ew, "\nDragging the score down")
+	for _, r := range reasons {
+		penalty := ""
+		if r.Penalty > 0 {
+			penalty = fmt.Sprintf(" (-%d)", r.Penalty)
+		}
+		fmt.Fprintf(ew, "  %s: %s%s\n", title(r.Service), r.Text, penalty)
+	}
+
+	return ew.err
+}
@@ -0,0 +1,115 @@
+package frain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestAssessHealth(t *testing.T) {
+	services := []*Service{
+		{Name: "github", Indicator: "none", Components: []Component{{Name: "API", Status: "operational"}}},
+		{
+			Name:       "circleci",
+			Indicator:  "minor",
+			Components: []Component{{Name: "Pipelines", Status: "partial_outage"}},
+			Incidents: []Incident{
+				{Name: "Slow builds", Impact: "minor", Status: "investigating"},
+				{Name: "Old outage", Impact: "critical", Status: "resolved"},
+			},
+		},
+		{Name: "fastly", Indicator: "none", Components: []Component{{Name: "CDN", Status: "degraded_performance"}}},
+	}
+	names := []string{"github", "circleci", "fastly"}
+
+	h := AssessHealth(names, services, HealthConfig{})
+	scores := []int{100, 60, 80}
+	for i, s := range h.Services {
+		if s.Score != scores[i] {
+			t.Errorf("%s: expected score %d, got %d", s.Service, scores[i], s.Score)
+		}
+	}
+	if h.Score != 80 || h.Level != HealthWarning {
+		t.Errorf("expected warning with score 80, got %v with %d", h.Level, h.Score)
+	}
+
+	reasons := h.Reasons()
+	if len(reasons) != 4 || reasons[0].Text != "component Pipelines is partial outage" {
+		t.Errorf("unexpected reasons %+v", reasons)
+	}
+
+	// a critical component counts double and a light weight softens the service
+	h = AssessHealth(names, services, HealthConfig{
+		Weights:            map[string]float64{"circleci": 0.5},
+		CriticalComponents: map[string][]string{"fastly": {"cdn"}},
+	})
+	if s := h.Services[2]; s.Score != 60 || !strings.HasPrefix(s.Reasons[0].Text, "critical ") {
+		t.Errorf("expected the critical component to count double, got %+v", s)
+	}
+	if h.Score != 76 {
+		t.Errorf("expected a weighted score of 76, got %d", h.Score)
+	}
+}
+
+func TestAssessHealthLevels(t *testing.T) {
+	down := &Service{Name: "fastly", Indicator: "major"}
+	up := &Service{Name: "github", Indicator: "none"}
+
+	if h := AssessHealth([]string{"github", "fastly"}, []*Service{up, down}, HealthConfig{}); h.Level != HealthCritical {
+		t.Errorf("expected critical, got %v", h.Level)
+	}
+
+	// a service weighing nothing does not set the level
+	cfg := HealthConfig{Weights: map[string]float64{"fastly": 0}}
+	if h := AssessHealth([]string{"github", "fastly"}, []*Service{up, down}, cfg); h.Level != HealthOK || h.Score != 100 {
+		t.Errorf("expected ok with score 100, got %v with %d", h.Level, h.Score)
+	}
+
+	// a light service down is a warning, a very light one no concern
+	for weight, want := range map[float64]HealthLevel{0.5: HealthWarning, 0.1: HealthOK, 2: HealthCritical} {
+		cfg := HealthConfig{Weights: map[string]float64{"fastly": weight}}
+		if h := AssessHealth([]string{"github", "fastly"}, []*Service{up, down}, cfg); h.Level != want || h.Services[1].Level != HealthCritical {
+			t.Errorf("weight %v: expected %v overall with fastly critical, got %v and %v", weight, want, h.Level, h.Services[1].Level)
+		}
+	}
+
+	if h := AssessHealth([]string{"github", "fastly"}, []*Service{up, nil}, HealthConfig{}); h.Level != HealthWarning {
+		t.Errorf("expected a missing service to lower the level to warning, got %v", h.Level)
+	}
+
+	if h := AssessHealth([]string{"fastly"}, []*Service{nil}, HealthConfig{}); h.Level != HealthUnknown {
+		t.Errorf("expected unknown, got %v", h.Level)
+	}
+}
+
+func TestHealthWriteText(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	h := AssessHealth(
+		[]string{"github", "fastly"},
+		[]*Service{{Name: "github", Indicator: "none"}, nil},
+		HealthConfig{},
+	)
+
+	var buf bytes.Buffer
+	if err := h.WriteText(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `Health: warning (score 100/100)
+
+SERVICE  SCORE  LEVEL
+Github   100    ok
+Fastly   -      unknown
+
+Dragging the score down
+  Fastly: could not be fetched
+`
+	if got := buf.String(); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+}
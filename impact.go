@@ -0,0 +1,235 @@
+package frain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// System is one of our own systems and the vendor services it depends on
+type System struct {
+	Name      string       `json:"name"`
+	DependsOn []Dependency `json:"dependsOn"`
+}
+
+// Dependency is a vendor service, or a single component of it, that a system relies on
+type Dependency struct {
+	Service string `json:"service"`
+	// Component narrows the dependency down to one component of the service
+	Component string `json:"component,omitempty"`
+	// Optional dependencies are worked around, so they never affect a system worse than
+	// a minor impact
+	Optional bool `json:"optional,omitempty"`
+}
+
+// ImpactCause is a vendor problem affecting a system
+type ImpactCause struct {
+	Service   string `json:"service"`
+	Component string `json:"component,omitempty"`
+	Impact    Impact `json:"impact"`
+	Text      string `json:"text"`
+}
+
+// SystemImpact tells how badly a system is affected by the problems of its vendors.
+// The impact is ImpactNone for an unaffected system and ImpactUnknown when one of its
+// vendors could not be fetched and no other one is known to affect it.
+type SystemImpact struct {
+	System string        `json:"system"`
+	Impact Impact        `json:"impact"`
+	Causes []ImpactCause `json:"causes,omitempty"`
+}
+
+// componentImpacts maps the status of a component to the impact on its dependants
+var componentImpacts = map[ComponentStatus]Impact{
+	ComponentOperational:         ImpactNone,
+	ComponentUnderMaintenance:    ImpactMaintenance,
+	ComponentDegradedPerformance: ImpactMinor,
+	ComponentPartialOutage:       ImpactMajor,
+	ComponentMajorOutage:         ImpactCritical,
+}
+
+// indicatorImpacts maps the indicator of a service to the impact on its dependants
+var indicatorImpacts = map[Indicator]Impact{
+	IndicatorNone:        ImpactNone,
+	IndicatorMaintenance: ImpactMaintenance,
+	IndicatorMinor:       ImpactMinor,
+	IndicatorMajor:       ImpactMajor,
+	IndicatorCritical:    ImpactCritical,
+}
+
+// BlastRadius works out which systems are affected by the current state of the vendor
+// services they depend on, the worst affected first. services holds the fetched vendor
+// services by name; a service missing from it is reported as unknown.
+//
+// A dependency on a whole service is affected by its indicator, its components and its
+// unresolved incidents. A dependency on a component is affected by the status of the
+// component and by the unresolved incidents naming it in their title or description,
+// as incidents do not list the components they affect.
+func BlastRadius(systems []System, services map[string]*Service) []SystemImpact {
+	var impacts []SystemImpact
+	for _, sys := range systems {
+		si := SystemImpact{System: sys.Name, Impact: ImpactNone}
+		unknown := false
+
+		for _, dep := range sys.DependsOn {
+			s, ok := services[strings.ToLower(dep.Service)]
+			if !ok || s == nil {
+				unknown = true
+				si.Causes = append(si.Causes, ImpactCause{
+					Service:   dep.Service,
+					Component: dep.Component,
+					Impact:    ImpactUnknown,
+					Text:      fmt.Sprintf("%s could not be fetched", title(dep.Service)),
+				})
+				continue
+			}
+
+			for _, cause := range dependencyCauses(dep, s) {
+				if dep.Optional && cause.Impact > ImpactMinor {
+					cause.Impact = ImpactMinor
+					cause.Text += " (optional)"
+				}
+				if cause.Impact == ImpactUnknown {
+					unknown = true
+				}
+				if cause.Impact > si.Impact {
+					si.Impact = cause.Impact
+				}
+				si.Causes = append(si.Causes, cause)
+			}
+		}
+
+		if unknown && si.Impact == ImpactNone {
+			si.Impact = ImpactUnknown
+		}
+		sort.SliceStable(si.Causes, func(a, b int) bool { return si.Causes[a].Impact > si.Causes[b].Impact })
+		impacts = append(impacts, si)
+	}
+
+	sort.SliceStable(impacts, func(a, b int) bool { return impacts[a].Impact > impacts[b].Impact })
+
+	return impacts
+}
+
+// dependencyCauses returns the problems of s that affect the dependency
+func dependencyCauses(dep Dependency, s *Service) []ImpactCause {
+	var causes []ImpactCause
+	name := title(s.Name)
+
+	if dep.Component == "" {
+		if impact := indicatorImpacts[ParseIndicator(s.Indicator)]; impact > ImpactNone {
+			causes = append(causes, ImpactCause{
+				Service: s.Name,
+				Impact:  impact,
+				Text:    fmt.Sprintf("%s status page reports a %s problem", name, s.Indicator),
+			})
+		}
+	}
+
+	found := dep.Component == ""
+	for _, c := range s.Components {
+		if dep.Component != "" && !strings.EqualFold(c.Name, dep.Component) {
+			continue
+		}
+		found = true
+
+		status := ParseComponentStatus(c.Status)
+		if impact := componentImpacts[status]; impact > ImpactNone {
+			causes = append(causes, ImpactCause{
+				Service:   s.Name,
+				Component: c.Name,
+				Impact:    impact,
+				Text:      fmt.Sprintf("%s %s is %s", name, c.Name, strings.Replace(status.String(), "_", " ", -1)),
+			})
+		}
+	}
+	if !found {
+		causes = append(causes, ImpactCause{
+			Service:   s.Name,
+			Component: dep.Component,
+			Impact:    ImpactUnknown,
+			Text:      fmt.Sprintf("%s has no component named %s", name, dep.Component),
+		})
+	}
+
+	for _, i := range s.Incidents {
+		if ParseIncidentStatus(i.Status).Resolved() {
+			continue
+		}
+		if dep.Component != "" && !mentions(i, dep.Component) {
+			continue
+		}
+
+		impact := ParseImpact(i.Impact)
+		if impact == ImpactUnknown {
+			impact = ImpactMinor
+		}
+		causes = append(causes, ImpactCause{
+			Service:   s.Name,
+			Component: dep.Component,
+			Impact:    impact,
+			Text:      fmt.Sprintf("%s incident %q is %s", name, i.Name, strings.ToLower(i.Status)),
+		})
+	}
+
+	return causes
+}
+
+// mentions reports whether the title or current description of an incident names the
+// component
+func mentions(i Incident, component string) bool {
+	component = strings.ToLower(component)
+
+	return strings.Contains(strings.ToLower(i.Name), component) ||
+		strings.Contains(strings.ToLower(incidentDescription(i)), component)
+}
+
+// WriteImpactText writes the systems with their impact and its causes to w. Unaffected
+// systems are left out unless all is set.
+func WriteImpactText(w io.Writer, impacts []SystemImpact, all bool) error {
+	ew := &errWriter{w: w}
+
+	t := &table{header: []string{"SYSTEM", "IMPACT", "CAUSE"}}
+	for _, si := range impacts {
+		if si.Impact == ImpactNone && !all {
+			continue
+		}
+
+		impact := color.New(impactColour(si.Impact)).Sprint(si.Impact)
+		if len(si.Causes) == 0 {
+			t.add(si.System, impact, "-")
+			continue
+		}
+		for j, c := range si.Causes {
+			if j == 0 {
+				t.add(si.System, impact, c.Text)
+			} else {
+				t.add("", "", c.Text)
+			}
+		}
+	}
+
+	if len(t.rows) == 0 {
+		fmt.Fprintln(ew, "No system is affected by its vendors.")
+		return ew.err
+	}
+
+	t.write(ew)
+	return ew.err
+}
+
+func impactColour(i Impact) color.Attribute {
+	switch {
+	case i >= ImpactMajor:
+		return color.FgRed
+	case i >= ImpactMaintenance:
+		return color.FgYellow
+	case i == ImpactNone:
+		return color.FgGreen
+	}
+
+	return color.FgWhite
+}
@@ -0,0 +1,92 @@
+package frain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestBlastRadius(t *testing.T) {
+	services := map[string]*Service{
+		"circleci": {
+			Name:       "circleci",
+			Indicator:  "minor",
+			Components: []Component{{Name: "Pipelines", Status: "partial_outage"}, {Name: "macOS Jobs", Status: "operational"}},
+		},
+		"github": {
+			Name:       "github",
+			Indicator:  "none",
+			Components: []Component{{Name: "Git Operations", Status: "operational"}, {Name: "Webhooks", Status: "operational"}},
+			Incidents: []Incident{
+				{Name: "Delayed webhooks", Impact: "minor", Status: "investigating"},
+				{Name: "Git Operations failing", Impact: "critical", Status: "resolved"},
+			},
+		},
+	}
+	systems := []System{
+		{Name: "website", DependsOn: []Dependency{{Service: "fastly"}}},
+		{Name: "mobile builds", DependsOn: []Dependency{{Service: "circleci", Component: "macOS Jobs"}}},
+		{Name: "deploy pipeline", DependsOn: []Dependency{
+			{Service: "circleci", Component: "pipelines"},
+			{Service: "github", Component: "Git Operations"},
+		}},
+		{Name: "chatops", DependsOn: []Dependency{{Service: "github", Component: "Webhooks"}, {Service: "circleci", Optional: true}}},
+	}
+
+	impacts := BlastRadius(systems, services)
+	want := []struct {
+		system string
+		impact Impact
+		causes int
+	}{
+		{"deploy pipeline", ImpactMajor, 1},
+		{"chatops", ImpactMinor, 3},
+		{"mobile builds", ImpactNone, 0},
+		{"website", ImpactUnknown, 1},
+	}
+	if len(impacts) != len(want) {
+		t.Fatalf("expected %d systems, got %+v", len(want), impacts)
+	}
+	for i, w := range want {
+		si := impacts[i]
+		if si.System != w.system || si.Impact != w.impact || len(si.Causes) != w.causes {
+			t.Errorf("expected %s to be %v with %d cause(s), got %+v", w.system, w.impact, w.causes, si)
+		}
+	}
+	if text := impacts[0].Causes[0].Text; text != "Circleci Pipelines is partial outage" {
+		t.Errorf("unexpected cause %q", text)
+	}
+}
+
+func TestWriteImpactText(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	impacts := []SystemImpact{
+		{System: "deploy pipeline", Impact: ImpactMajor, Causes: []ImpactCause{
+			{Impact: ImpactMajor, Text: "Circleci Pipelines is partial outage"},
+			{Impact: ImpactMinor, Text: `Github incident "Slow pushes" is investigating`},
+		}},
+		{System: "website", Impact: ImpactNone},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteImpactText(&buf, impacts, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `SYSTEM           IMPACT  CAUSE
+deploy pipeline  major   Circleci Pipelines is partial outage
+                         Github incident "Slow pushes" is investigating
+`
+	if got := buf.String(); got != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, got)
+	}
+
+	buf.Reset()
+	WriteImpactText(&buf, impacts[1:], false)
+	if got := buf.String(); got != "No system is affected by its vendors.\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
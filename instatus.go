@@ -0,0 +1,161 @@
+package frain
+
+import (
+	"strings"
+	"time"
+)
+
+// InstatusProvider reads a service from the public JSON API of an Instatus page, given
+// the URL of the page. The API lists the unresolved incidents without their updates,
+// so each one is given a single update named after it.
+type InstatusProvider struct {
+	URL string
+	// Client fetches the page, DefaultClient when nil
+	Client *Client
+}
+
+type instatusSummary struct {
+	Page struct {
+		Name   string `json:"name"`
+		URL    string `json:"url"`
+		Status string `json:"status"`
+	} `json:"page"`
+	ActiveIncidents    []instatusEvent `json:"activeIncidents"`
+	ActiveMaintenances []instatusEvent `json:"activeMaintenances"`
+}
+
+// instatusEvent is an incident or a maintenance
+type instatusEvent struct {
+	ID      string    `json:"id"`
+	Name    string    `json:"name"`
+	Started time.Time `json:"started"`
+	Status  string    `json:"status"`
+	Impact  string    `json:"impact"`
+	URL     string    `json:"url"`
+	// Duration of a maintenance in minutes
+	Duration int `json:"duration"`
+}
+
+type instatusComponent struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Status      string              `json:"status"`
+	Description string              `json:"description"`
+	Children    []instatusComponent `json:"children"`
+}
+
+// instatusMaintenanceStatuses maps the statuses of a maintenance, the only ones not
+// named as in frain once the underscores are dropped
+var instatusMaintenanceStatuses = map[string]IncidentStatus{
+	"NOTSTARTEDYET": IncidentScheduled,
+	"INPROGRESS":    IncidentInProgress,
+	"COMPLETED":     IncidentCompleted,
+}
+
+// GetService implements the Provider interface
+func (p InstatusProvider) GetService(name string, startTime, endTime time.Time) (*Service, error) {
+	var summary instatusSummary
+	if err := getJSON(p.Client, p.URL+"/summary.json", &summary); err != nil {
+		return nil, err
+	}
+	var components struct {
+		Components []instatusComponent `json:"components"`
+	}
+	if err := getJSON(p.Client, p.URL+"/v2/components.json", &components); err != nil {
+		return nil, err
+	}
+
+	s := &Service{
+		Name:          name,
+		Provider:      ProviderInstatus,
+		Status:        summary.Page.Status,
+		StatusPageURL: firstOf(summary.Page.URL, p.URL),
+		IsActive:      true,
+	}
+
+	for _, c := range components.Components {
+		// groups have no status of their own
+		for _, child := range append([]instatusComponent{c}, c.Children...) {
+			if len(child.Children) > 0 {
+				continue
+			}
+			s.Components = append(s.Components, Component{
+				ID:          child.ID,
+				ComponentID: child.ID,
+				Name:        child.Name,
+				Status:      instatusComponentStatus(child.Status).String(),
+				Description: child.Description,
+			})
+		}
+	}
+
+	for _, e := range summary.ActiveIncidents {
+		impact := componentImpacts[instatusComponentStatus(e.Impact)]
+		if impact == ImpactUnknown {
+			impact = ImpactMinor
+		}
+
+		status := ParseIncidentStatus(e.Status)
+		s.Incidents = append(s.Incidents, Incident{
+			ID:         e.ID,
+			IncidentID: e.ID,
+			Name:       e.Name,
+			Status:     status.String(),
+			Impact:     impact.String(),
+			Shortlink:  e.URL,
+			IsActive:   !status.Resolved(),
+			CreatedAt:  e.Started,
+			UpdatedAt:  e.Started,
+			// the name stands in for the updates the API leaves out
+			IncidentUpdates: []IncidentUpdate{{
+				ID:         e.ID,
+				IncidentID: e.ID,
+				Status:     status.String(),
+				Body:       e.Name,
+				CreatedAt:  e.Started,
+				UpdatedAt:  e.Started,
+			}},
+		})
+	}
+	s.Incidents = createdBetween(s.Incidents, startTime, endTime)
+
+	for _, e := range summary.ActiveMaintenances {
+		m := Maintenance{
+			ID:           e.ID,
+			Name:         e.Name,
+			Status:       instatusMaintenanceStatuses[strings.ToUpper(e.Status)].String(),
+			Impact:       ImpactMaintenance.String(),
+			Shortlink:    e.URL,
+			ScheduledFor: e.Started,
+			CreatedAt:    e.Started,
+			UpdatedAt:    e.Started,
+		}
+		if e.Duration > 0 {
+			m.ScheduledUntil = e.Started.Add(time.Duration(e.Duration) * time.Minute)
+		}
+		s.Maintenances = append(s.Maintenances, m)
+	}
+
+	switch strings.ToUpper(summary.Page.Status) {
+	case "UP":
+		s.Indicator = IndicatorNone.String()
+	case "UNDERMAINTENANCE":
+		s.Indicator = IndicatorMaintenance.String()
+	default:
+		s.Indicator = worstIndicator(s.Components, s.Incidents)
+	}
+
+	return s, nil
+}
+
+// instatusComponentStatus reads a status written in capitals without separators, e.g.
+// PARTIALOUTAGE
+func instatusComponentStatus(s string) ComponentStatus {
+	for status, name := range componentStatuses {
+		if strings.EqualFold(strings.Replace(name, "_", "", -1), s) {
+			return ComponentStatus(status)
+		}
+	}
+
+	return ComponentUnknown
+}
@@ -0,0 +1,150 @@
+package frain
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// columnGap is the number of spaces between two columns of a table
+const columnGap = 2
+
+// ansiEscape matches the colour escape sequences written by fatih/color
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// displayWidth returns the number of terminal columns taken by s. Wide characters such
+// as CJK count twice and colour escape sequences do not count at all.
+func displayWidth(s string) int {
+	return runewidth.StringWidth(ansiEscape.ReplaceAllString(s, ""))
+}
+
+// truncate shortens s to at most width columns, ending it with "..." when it is cut
+func truncate(s string, width int) string {
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width <= 3 {
+		return runewidth.Truncate(s, width, "")
+	}
+
+	return runewidth.Truncate(s, width, "...")
+}
+
+// fill pads s with spaces to width columns
+func fill(s string, width int) string {
+	if n := displayWidth(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+
+	return s
+}
+
+// table lays out rows of cells in columns padded with spaces. Unlike text/tabwriter it
+// measures cells by their display width, so wide characters and colour escape sequences
+// do not throw the columns out of line.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// widths returns the width of every column
+func (t *table) widths() []int {
+	widths := make([]int, len(t.header))
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		for i, cell := range row {
+			if n := displayWidth(cell); i < len(widths) && n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	return widths
+}
+
+// write prints the header on a title bar followed by the rows. The last column is not
+// padded so that lines carry no trailing spaces.
+func (t *table) write(w io.Writer) {
+	widths := t.widths()
+	total := 0
+	for _, n := range widths {
+		total += n + columnGap
+	}
+
+	header := strings.TrimRight(t.line(t.header, widths), " ")
+	if !color.NoColor {
+		// the title bar spans the whole table
+		header = fill(header, total-columnGap)
+	}
+	titleBar.Fprint(w, header)
+	io.WriteString(w, "\n")
+	for _, row := range t.rows {
+		io.WriteString(w, strings.TrimRight(t.line(row, widths), " "))
+		io.WriteString(w, "\n")
+	}
+}
+
+func (t *table) line(cells []string, widths []int) string {
+	sb := strings.Builder{}
+	for i, cell := range cells {
+		if i == len(cells)-1 {
+			sb.WriteString(cell)
+			break
+		}
+		sb.WriteString(fill(cell, widths[i]+columnGap))
+	}
+
+	return sb.String()
+}
+
+// wrap breaks s into lines of at most width columns, at spaces where possible. Words
+// wider than a line, e.g. long identifiers or CJK text without spaces, are split.
+func wrap(s string, width int) []string {
+	if width < 0 {
+		return []string{"-"}
+	}
+	if width == 0 {
+		return strings.Split(s, "\n")
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line, lineWidth := "", 0
+		for _, word := range strings.Fields(paragraph) {
+			n := displayWidth(word)
+			switch {
+			case lineWidth == 0 && n <= width:
+				line, lineWidth = word, n
+				continue
+			case lineWidth > 0 && lineWidth+1+n <= width:
+				line, lineWidth = line+" "+word, lineWidth+1+n
+				continue
+			}
+
+			if lineWidth > 0 {
+				lines = append(lines, line)
+				line, lineWidth = "", 0
+			}
+			for n > width {
+				head := runewidth.Truncate(word, width, "")
+				if head == "" {
+					// a single character wider than the line
+					head = string([]rune(word)[:1])
+				}
+				lines = append(lines, head)
+				word = word[len(head):]
+				n = displayWidth(word)
+			}
+			line, lineWidth = word, n
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,180 @@
+package frain
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Maintenance contains information about a maintenance window scheduled by a service
+type Maintenance struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Impact    string `json:"impact"`
+	Shortlink string `json:"shortlink"`
+
+	ScheduledFor   time.Time `json:"scheduledFor"`
+	ScheduledUntil time.Time `json:"scheduledUntil"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+
+	// Components lists the components taken down by the maintenance, all of them when empty
+	Components      []Component      `json:"components"`
+	IncidentUpdates []IncidentUpdate `json:"incidentUpdates"`
+}
+
+// InProgress reports whether the maintenance is under way at t, either because the
+// status page says so or because t falls within its window and it is not completed yet
+func (m Maintenance) InProgress(t time.Time) bool {
+	status := ParseIncidentStatus(m.Status)
+	switch {
+	case status.Resolved():
+		return false
+	case status == IncidentInProgress || status == IncidentVerifying:
+		return true
+	case m.ScheduledFor.IsZero():
+		return false
+	}
+
+	return !t.Before(m.ScheduledFor) && (m.ScheduledUntil.IsZero() || t.Before(m.ScheduledUntil))
+}
+
+// Upcoming reports whether the maintenance is yet to start at t
+func (m Maintenance) Upcoming(t time.Time) bool {
+	return !ParseIncidentStatus(m.Status).Resolved() && !m.InProgress(t) && t.Before(m.ScheduledFor)
+}
+
+// Covers reports whether the maintenance takes the component down
+func (m Maintenance) Covers(component string) bool {
+	if len(m.Components) == 0 {
+		return true
+	}
+	for _, c := range m.Components {
+		if strings.EqualFold(c.Name, component) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PlannedMaintenances returns the maintenances of s in progress or upcoming at t, the
+// earliest first
+func (s *Service) PlannedMaintenances(t time.Time) []Maintenance {
+	var planned []Maintenance
+	for _, m := range s.Maintenances {
+		if m.InProgress(t) || m.Upcoming(t) {
+			planned = append(planned, m)
+		}
+	}
+	sort.SliceStable(planned, func(a, b int) bool { return planned[a].ScheduledFor.Before(planned[b].ScheduledFor) })
+
+	return planned
+}
+
+// UnderMaintenance reports whether the component of s is down for planned maintenance
+// at t
+func (s *Service) UnderMaintenance(component string, t time.Time) bool {
+	for _, c := range s.Components {
+		if strings.EqualFold(c.Name, component) && ParseComponentStatus(c.Status) == ComponentUnderMaintenance {
+			return true
+		}
+	}
+	for _, m := range s.Maintenances {
+		if m.InProgress(t) && m.Covers(component) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithoutMaintenance returns a copy of s with its planned downtime at t left out, for
+// alerting on unplanned problems only. Components under maintenance are reported as
+// operational, a maintenance indicator as none, and incidents with a maintenance impact
+// or status are dropped.
+func WithoutMaintenance(s *Service, t time.Time) *Service {
+	if s == nil {
+		return nil
+	}
+
+	c := *s
+	if ParseIndicator(c.Indicator) == IndicatorMaintenance {
+		c.Indicator = IndicatorNone.String()
+	}
+
+	c.Components = make([]Component, len(s.Components))
+	for i, comp := range s.Components {
+		if s.UnderMaintenance(comp.Name, t) {
+			comp.Status = ComponentOperational.String()
+		}
+		c.Components[i] = comp
+	}
+
+	c.Incidents = nil
+	for _, i := range s.Incidents {
+		if ParseImpact(i.Impact) == ImpactMaintenance || ParseIncidentStatus(i.Status) >= IncidentScheduled {
+			continue
+		}
+		c.Incidents = append(c.Incidents, i)
+	}
+
+	return &c
+}
+
+// WriteMaintenanceText writes the maintenances of s in progress or upcoming at now to
+// w. Completed maintenances are listed as well when all is set.
+func WriteMaintenanceText(w io.Writer, s *Service, now time.Time, all bool) error {
+	ew := &errWriter{w: w}
+	bold.Fprintf(ew, "%s Scheduled Maintenance\n", title(s.Name))
+
+	maintenances := s.PlannedMaintenances(now)
+	if all {
+		maintenances = append([]Maintenance(nil), s.Maintenances...)
+		sort.SliceStable(maintenances, func(a, b int) bool {
+			return maintenances[a].ScheduledFor.Before(maintenances[b].ScheduledFor)
+		})
+	}
+	if len(maintenances) == 0 {
+		fmt.Fprintln(ew, "No scheduled maintenance")
+		return ew.err
+	}
+
+	t := &table{header: []string{"STATUS", "STARTS", "ENDS", "NAME", "COMPONENTS"}}
+	for _, m := range maintenances {
+		// overdue and completed maintenances keep the status of their page
+		status := strings.Title(strings.Replace(m.Status, "_", " ", -1))
+		switch {
+		case m.InProgress(now):
+			status = "In Progress"
+		case m.Upcoming(now):
+			status = "Upcoming"
+		}
+
+		components := "All"
+		if len(m.Components) > 0 {
+			var names []string
+			for _, c := range m.Components {
+				names = append(names, c.Name)
+			}
+			components = strings.Join(names, ", ")
+		}
+
+		t.add(Render(status), maintenanceTime(m.ScheduledFor), maintenanceTime(m.ScheduledUntil), m.Name, components)
+	}
+
+	fmt.Fprintln(ew)
+	t.write(ew)
+	return ew.err
+}
+
+func maintenanceTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Format("Jan 2, 2006 15:04 MST")
+}
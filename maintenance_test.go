@@ -0,0 +1,125 @@
+package frain
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func maintenanceService() *Service {
+	at := func(day, hour int) time.Time { return time.Date(2019, 6, day, hour, 0, 0, 0, time.UTC) }
+
+	return &Service{
+		Name:      "github",
+		Indicator: "maintenance",
+		Components: []Component{
+			{Name: "API", Status: "operational"},
+			{Name: "Pages", Status: "partial_outage"},
+			{Name: "Actions", Status: "under_maintenance"},
+			{Name: "Webhooks", Status: "degraded_performance"},
+		},
+		Incidents: []Incident{
+			{Name: "Pages upgrade", Impact: "maintenance", Status: "in_progress"},
+			{Name: "Delayed webhooks", Impact: "minor", Status: "investigating"},
+		},
+		Maintenances: []Maintenance{
+			{Name: "Database failover", Status: "completed", ScheduledFor: at(1, 3), ScheduledUntil: at(1, 4)},
+			{Name: "Network upgrade", Status: "scheduled", ScheduledFor: at(9, 2), ScheduledUntil: at(9, 4)},
+			{
+				Name: "Pages upgrade", Status: "scheduled", ScheduledFor: at(3, 8), ScheduledUntil: at(3, 12),
+				Components: []Component{{Name: "pages"}},
+			},
+		},
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	now := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+	s := maintenanceService()
+
+	planned := s.PlannedMaintenances(now)
+	if len(planned) != 2 || planned[0].Name != "Pages upgrade" || planned[1].Name != "Network upgrade" {
+		t.Fatalf("unexpected planned maintenances %+v", planned)
+	}
+	if !planned[0].InProgress(now) || planned[0].Upcoming(now) {
+		t.Errorf("expected %q to be in progress", planned[0].Name)
+	}
+	if planned[1].InProgress(now) || !planned[1].Upcoming(now) {
+		t.Errorf("expected %q to be upcoming", planned[1].Name)
+	}
+
+	// the status page may start a maintenance ahead of its window
+	early := Maintenance{Status: "in_progress", ScheduledFor: now.Add(time.Hour)}
+	if !early.InProgress(now) {
+		t.Error("expected an in progress status to win over the window")
+	}
+
+	for component, want := range map[string]bool{"Pages": true, "Actions": true, "API": false, "Webhooks": false} {
+		if got := s.UnderMaintenance(component, now); got != want {
+			t.Errorf("%s: expected under maintenance %v, got %v", component, want, got)
+		}
+	}
+}
+
+func TestWithoutMaintenance(t *testing.T) {
+	now := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+	s := maintenanceService()
+
+	c := WithoutMaintenance(s, now)
+	if c.Indicator != "none" {
+		t.Errorf("expected the maintenance indicator to be cleared, got %s", c.Indicator)
+	}
+	statuses := []string{"operational", "operational", "operational", "degraded_performance"}
+	for i, comp := range c.Components {
+		if comp.Status != statuses[i] {
+			t.Errorf("%s: expected %s, got %s", comp.Name, statuses[i], comp.Status)
+		}
+	}
+	if len(c.Incidents) != 1 || c.Incidents[0].Name != "Delayed webhooks" {
+		t.Errorf("expected the unplanned incident only, got %+v", c.Incidents)
+	}
+	if s.Components[1].Status != "partial_outage" || len(s.Incidents) != 2 {
+		t.Error("expected the original service to be left untouched")
+	}
+
+	// planned downtime no longer drags the health down
+	h := AssessHealth([]string{"github"}, []*Service{c}, HealthConfig{})
+	if h.Services[0].Score != 80 {
+		t.Errorf("expected the degraded webhooks only to count, got %+v", h.Services[0])
+	}
+}
+
+func TestWriteMaintenanceText(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	now := time.Date(2019, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := WriteMaintenanceText(&buf, maintenanceService(), now, false); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "In Progress  Jun 3, 2019 08:00 UTC  Jun 3, 2019 12:00 UTC  Pages upgrade    pages") {
+		t.Errorf("expected the in progress maintenance, got:\n%s", out)
+	}
+	if !strings.Contains(out, "Upcoming") || strings.Contains(out, "Database failover") {
+		t.Errorf("expected upcoming maintenances only, got:\n%s", out)
+	}
+
+	buf.Reset()
+	WriteMaintenanceText(&buf, maintenanceService(), now, true)
+	if !strings.Contains(buf.String(), "Completed") {
+		t.Errorf("expected the completed maintenance, got:\n%s", buf.String())
+	}
+
+	buf.Reset()
+	WriteMaintenanceText(&buf, &Service{Name: "fastly"}, now, false)
+	if !strings.Contains(buf.String(), "No scheduled maintenance") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
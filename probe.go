@@ -0,0 +1,281 @@
+package frain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Probe types
+const (
+	ProbeHTTP = "http"
+	ProbeTCP  = "tcp"
+	ProbeDNS  = "dns"
+)
+
+// DefaultProbeTimeout is how long a probe waits when its timeout is unset
+const DefaultProbeTimeout = 10 * time.Second
+
+// Duration is a time.Duration read from and written to JSON as a string such as "500ms"
+type Duration time.Duration
+
+// UnmarshalJSON reads a duration string, or a number of nanoseconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("bad duration %s", data)
+		}
+		*d = Duration(n)
+		return nil
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Probe is a check of a vendor endpoint we use, made by us rather than reported by the
+// vendor: an HTTP GET, a TCP connection or a DNS resolution
+type Probe struct {
+	// Name of the synthetic component, the target followed by "(probe)" when empty
+	Name string `json:"name,omitempty"`
+	// Type is ProbeHTTP, ProbeTCP or ProbeDNS
+	Type string `json:"type"`
+	// Target is a URL for HTTP, a host:port for TCP and a host name for DNS
+	Target string `json:"target"`
+	// Timeout fails the probe, DefaultProbeTimeout when unset
+	Timeout Duration `json:"timeout,omitempty"`
+	// Slow is the latency above which the endpoint is degraded, no limit when unset
+	Slow Duration `json:"slow,omitempty"`
+	// ExpectStatus is the HTTP status code expected, any code below 400 when unset
+	ExpectStatus int `json:"expectStatus,omitempty"`
+	// Component is the vendor component the probe observes, against which Correlate
+	// compares it
+	Component string `json:"component,omitempty"`
+}
+
+// ProbeResult is the outcome of running a probe
+type ProbeResult struct {
+	Probe   Probe           `json:"probe"`
+	Status  ComponentStatus `json:"status"`
+	Latency Duration        `json:"latency"`
+	Error   string          `json:"error,omitempty"`
+	Time    time.Time       `json:"time"`
+}
+
+// ComponentName returns the name of the synthetic component of the probe
+func (p Probe) ComponentName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	target := p.Target
+	if u, err := url.Parse(p.Target); err == nil && u.Host != "" {
+		target = u.Host
+	}
+
+	return target + " (probe)"
+}
+
+// validate checks that the probe can be run
+func (p Probe) validate() error {
+	switch p.Type {
+	case ProbeHTTP, ProbeTCP, ProbeDNS:
+	default:
+		return fmt.Errorf("unknown probe type '%s', expected http, tcp or dns", p.Type)
+	}
+	if p.Target == "" {
+		return fmt.Errorf("%s probe without a target", p.Type)
+	}
+
+	return nil
+}
+
+func (p Probe) timeout() time.Duration {
+	if p.Timeout > 0 {
+		return time.Duration(p.Timeout)
+	}
+
+	return DefaultProbeTimeout
+}
+
+// Run checks the endpoint once. A failed check makes a major outage and one slower than
+// the Slow latency a degraded performance.
+func (p Probe) Run() ProbeResult {
+	r := ProbeResult{Probe: p, Time: time.Now()}
+
+	var err error
+	if err = p.validate(); err == nil {
+		switch p.Type {
+		case ProbeHTTP:
+			err = p.get()
+		case ProbeTCP:
+			err = p.dial()
+		case ProbeDNS:
+			err = p.resolve()
+		}
+	}
+	r.Latency = Duration(time.Since(r.Time))
+
+	switch {
+	case err != nil:
+		r.Status = ComponentMajorOutage
+		r.Error = err.Error()
+	case p.Slow > 0 && r.Latency > p.Slow:
+		r.Status = ComponentDegradedPerformance
+	default:
+		r.Status = ComponentOperational
+	}
+
+	return r
+}
+
+func (p Probe) get() error {
+	c := &http.Client{Timeout: p.timeout()}
+	resp, err := c.Get(p.Target)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if p.ExpectStatus != 0 && resp.StatusCode != p.ExpectStatus {
+		return fmt.Errorf("expected status %d, got %s", p.ExpectStatus, resp.Status)
+	}
+	if p.ExpectStatus == 0 && resp.StatusCode >= 400 {
+		return fmt.Errorf("the server responded with %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (p Probe) dial() error {
+	conn, err := net.DialTimeout("tcp", p.Target, p.timeout())
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
+
+func (p Probe) resolve() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(ctx, p.Target)
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no address found for %s", p.Target)
+	}
+
+	return err
+}
+
+// RunProbes runs the probes concurrently and returns their results in the same order
+func RunProbes(probes []Probe) []ProbeResult {
+	results := make([]ProbeResult, len(probes))
+
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func(i int, p Probe) {
+			defer wg.Done()
+			results[i] = p.Run()
+		}(i, p)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Component turns the result into a synthetic component, to be shown next to those
+// reported by the vendor
+func (r ProbeResult) Component() Component {
+	latency := time.Duration(r.Latency).Round(time.Millisecond)
+	description := fmt.Sprintf("%s %s answered in %s", strings.ToUpper(r.Probe.Type), r.Probe.Target, latency)
+	switch r.Status {
+	case ComponentMajorOutage:
+		description = fmt.Sprintf("%s %s failed: %s", strings.ToUpper(r.Probe.Type), r.Probe.Target, r.Error)
+	case ComponentDegradedPerformance:
+		description += fmt.Sprintf(", slower than %s", time.Duration(r.Probe.Slow))
+	}
+
+	return Component{
+		ID:          "probe:" + r.Probe.Target,
+		Name:        r.Probe.ComponentName(),
+		Status:      r.Status.String(),
+		Description: description,
+		UpdatedAt:   r.Time,
+	}
+}
+
+// IsProbe reports whether the component is a synthetic one made from a probe result
+func (c Component) IsProbe() bool {
+	return strings.HasPrefix(c.ID, "probe:")
+}
+
+// AddProbeResults appends the synthetic components of the results to those of s
+func AddProbeResults(s *Service, results []ProbeResult) {
+	for _, r := range results {
+		s.Components = append(s.Components, r.Component())
+	}
+}
+
+// ServiceProbes holds the results of the probes of a service
+type ServiceProbes struct {
+	Service string        `json:"service"`
+	Results []ProbeResult `json:"results"`
+}
+
+// WriteProbeText writes the probe results of services to w
+func WriteProbeText(w io.Writer, probes []ServiceProbes) error {
+	ew := &errWriter{w: w}
+
+	t := &table{header: []string{"SERVICE", "PROBE", "STATUS", "LATENCY", "DETAIL"}}
+	for _, sp := range probes {
+		for j, r := range sp.Results {
+			service := ""
+			if j == 0 {
+				service = title(sp.Service)
+			}
+
+			latency := time.Duration(r.Latency).Round(time.Millisecond).String()
+			detail := r.Error
+			if r.Status == ComponentDegradedPerformance {
+				detail = fmt.Sprintf("slower than %s", time.Duration(r.Probe.Slow))
+			}
+			if detail == "" {
+				detail = "-"
+			}
+
+			status := strings.Title(strings.Replace(r.Status.String(), "_", " ", -1))
+			t.add(service, r.Probe.ComponentName(), Render(status), latency, detail)
+		}
+	}
+
+	if len(t.rows) == 0 {
+		fmt.Fprintln(ew, "No probe configured")
+		return ew.err
+	}
+
+	t.write(ew)
+	return ew.err
+}
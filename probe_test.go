@@ -0,0 +1,146 @@
+package frain
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+func probeServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/created":
+			w.WriteHeader(http.StatusCreated)
+		}
+	}))
+}
+
+func TestHTTPProbe(t *testing.T) {
+	srv := probeServer()
+	defer srv.Close()
+
+	tests := []struct {
+		probe Probe
+		want  ComponentStatus
+	}{
+		{Probe{Type: ProbeHTTP, Target: srv.URL + "/"}, ComponentOperational},
+		{Probe{Type: ProbeHTTP, Target: srv.URL + "/slow", Slow: Duration(10 * time.Millisecond)}, ComponentDegradedPerformance},
+		{Probe{Type: ProbeHTTP, Target: srv.URL + "/slow", Timeout: Duration(10 * time.Millisecond)}, ComponentMajorOutage},
+		{Probe{Type: ProbeHTTP, Target: srv.URL + "/broken"}, ComponentMajorOutage},
+		{Probe{Type: ProbeHTTP, Target: srv.URL + "/created", ExpectStatus: 200}, ComponentMajorOutage},
+		{Probe{Type: ProbeHTTP, Target: srv.URL + "/broken", ExpectStatus: 500}, ComponentOperational},
+		{Probe{Type: "ping", Target: srv.URL}, ComponentMajorOutage},
+	}
+	for _, tt := range tests {
+		if r := tt.probe.Run(); r.Status != tt.want {
+			t.Errorf("%s %s: expected %v, got %v (%s)", tt.probe.Type, tt.probe.Target, tt.want, r.Status, r.Error)
+		}
+	}
+}
+
+func TestTCPAndDNSProbes(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+
+	if r := (Probe{Type: ProbeTCP, Target: addr}).Run(); r.Status != ComponentOperational {
+		t.Errorf("expected the listener to be reachable, got %v (%s)", r.Status, r.Error)
+	}
+	l.Close()
+	if r := (Probe{Type: ProbeTCP, Target: addr, Timeout: Duration(time.Second)}).Run(); r.Status != ComponentMajorOutage {
+		t.Errorf("expected the closed listener to fail, got %v", r.Status)
+	}
+
+	if r := (Probe{Type: ProbeDNS, Target: "localhost"}).Run(); r.Status != ComponentOperational {
+		t.Errorf("expected localhost to resolve, got %v (%s)", r.Status, r.Error)
+	}
+}
+
+func TestProbeComponents(t *testing.T) {
+	srv := probeServer()
+	defer srv.Close()
+
+	probes := []Probe{
+		{Type: ProbeHTTP, Target: srv.URL + "/broken"},
+		{Name: "API", Type: ProbeHTTP, Target: srv.URL + "/"},
+	}
+	results := RunProbes(probes)
+
+	s := &Service{Name: "github", Components: []Component{{Name: "Git Operations", Status: "operational"}}}
+	AddProbeResults(s, results)
+	if len(s.Components) != 3 {
+		t.Fatalf("expected 2 synthetic components, got %+v", s.Components)
+	}
+
+	broken := s.Components[1]
+	host := strings.TrimPrefix(srv.URL, "http://")
+	if broken.Name != host+" (probe)" || broken.Status != "major_outage" || !broken.IsProbe() {
+		t.Errorf("unexpected component %+v", broken)
+	}
+	if !strings.Contains(broken.Description, "500 Internal Server Error") {
+		t.Errorf("expected the failure in the description, got %q", broken.Description)
+	}
+	if s.Components[2].Name != "API" || s.Components[0].IsProbe() {
+		t.Errorf("unexpected components %+v", s.Components)
+	}
+
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	var buf bytes.Buffer
+	if err := WriteProbeText(&buf, []ServiceProbes{{Service: "github", Results: results}}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Github   "+host+" (probe)  Major Outage") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
+
+func TestProbeConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "frain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+
+	ioutil.WriteFile(path, []byte(`{"services": [{"name": "GH", "probes": [
+		{"type": "HTTP", "target": "https://api.github.com", "slow": "500ms", "timeout": "2s"}
+	]}]}`), 0644)
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	probes := cfg.Probes("github")
+	if len(probes) != 1 {
+		t.Fatalf("expected the probe of the aliased service, got %+v", probes)
+	}
+	if probes[0].Type != ProbeHTTP || probes[0].Slow != Duration(500*time.Millisecond) {
+		t.Errorf("unexpected probes %+v", probes)
+	}
+	if data, _ := json.Marshal(probes[0].Timeout); string(data) != `"2s"` {
+		t.Errorf("expected the timeout as a string, got %s", data)
+	}
+
+	ioutil.WriteFile(path, []byte(`{"services": [{"name": "github", "probes": [{"type": "icmp", "target": "github.com"}]}]}`), 0644)
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "unknown probe type") {
+		t.Errorf("expected an unknown probe type error, got %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package frain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of status page read by NewProvider
+const (
+	ProviderStatusIO = "statusio"
+	ProviderInstatus = "instatus"
+	ProviderCachet   = "cachet"
+)
+
+var errProviderURL = errors.New("a provider needs the url of the status page")
+
+// Provider fetches a service along with its incidents created between the start and end
+// days. Client, which asks the frain backend, is the default provider; the others read
+// the status page of a vendor the backend does not know about.
+type Provider interface {
+	GetService(name string, startTime, endTime time.Time) (*Service, error)
+}
+
+// NewProvider returns the provider reading the status page of the given kind found at
+// url, fetched with DefaultClient
+func NewProvider(kind, url string) (Provider, error) {
+	url = strings.TrimRight(strings.TrimSpace(url), "/")
+	if url == "" {
+		return nil, errProviderURL
+	}
+
+	switch strings.ToLower(kind) {
+	case ProviderStatusIO:
+		return StatusIOProvider{URL: url}, nil
+	case ProviderInstatus:
+		return InstatusProvider{URL: url}, nil
+	case ProviderCachet:
+		return CachetProvider{URL: url}, nil
+	}
+
+	return nil, fmt.Errorf("unknown provider '%s', expected statusio, instatus or cachet", kind)
+}
+
+// createdBetween returns the incidents created from the start day up to and including
+// the end day, as the frain backend does
+func createdBetween(incidents []Incident, startTime, endTime time.Time) []Incident {
+	start := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, startTime.Location())
+	end := time.Date(endTime.Year(), endTime.Month(), endTime.Day()+1, 0, 0, 0, 0, endTime.Location())
+
+	var kept []Incident
+	for _, i := range incidents {
+		if !i.CreatedAt.Before(start) && i.CreatedAt.Before(end) {
+			kept = append(kept, i)
+		}
+	}
+
+	return kept
+}
+
+// getJSON fetches url with c, DefaultClient when nil, and decodes the response into v
+func getJSON(c *Client, url string, v interface{}) error {
+	if c == nil {
+		c = DefaultClient
+	}
+
+	resp, err := c.get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errJSONDecode
+	}
+
+	return nil
+}
+
+// worstIndicator returns the indicator of a service from the status of its components
+// and the impact of its unresolved incidents, for status pages without one
+func worstIndicator(components []Component, incidents []Incident) string {
+	worst := ImpactNone
+	for _, c := range components {
+		if impact := componentImpacts[ParseComponentStatus(c.Status)]; impact > worst {
+			worst = impact
+		}
+	}
+	for _, i := range incidents {
+		if impact := ParseImpact(i.Impact); i.IsActive && impact > worst {
+			worst = impact
+		}
+	}
+
+	return worst.String()
+}
+
+// sortUpdates orders incident updates the latest first, as the frain backend does
+func sortUpdates(updates []IncidentUpdate) {
+	sort.SliceStable(updates, func(a, b int) bool { return updates[a].CreatedAt.After(updates[b].CreatedAt) })
+}
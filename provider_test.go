@@ -0,0 +1,204 @@
+package frain
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// providerServer serves the recorded responses of a status page found in
+// testdata/providers/<dir>. A path such as /api/v1/incidents/11/updates is served from
+// api_v1_incidents_11_updates.json in Cachet style, and any other from the file at
+// that path. Links to http://cachet.test point back to the server.
+func providerServer(t *testing.T, dir string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(r.URL.Path, "/")
+		if dir == "cachet" {
+			name = strings.Replace(strings.TrimPrefix(name, "api/v1/"), "/", "_", -1) + ".json"
+			if page := r.URL.Query().Get("page"); page != "" && page != "1" {
+				name = strings.TrimSuffix(name, ".json") + "_page" + page + ".json"
+			}
+			if r.URL.Query().Get("per_page") == "" {
+				t.Errorf("expected a page size in %s", r.URL)
+			}
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "providers", dir, filepath.FromSlash(name)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(strings.Replace(string(data), "http://cachet.test", srv.URL, -1)))
+	}))
+
+	return srv
+}
+
+var (
+	providerStart = time.Date(2019, 5, 20, 0, 0, 0, 0, time.UTC)
+	providerEnd   = time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC)
+)
+
+func TestStatusIOProvider(t *testing.T) {
+	srv := providerServer(t, "statusio")
+	defer srv.Close()
+
+	c, _ := testClient(srv.URL, 0)
+	s, err := StatusIOProvider{URL: srv.URL + "/status.json", Client: c}.GetService("acme", providerStart, providerEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Name != "acme" || s.Provider != ProviderStatusIO || s.Indicator != "major" {
+		t.Errorf("unexpected service %+v", s)
+	}
+	statuses := []string{"operational", "partial_outage", "under_maintenance"}
+	if len(s.Components) != len(statuses) {
+		t.Fatalf("expected %d components, got %+v", len(statuses), s.Components)
+	}
+	for j, status := range statuses {
+		if s.Components[j].Status != status {
+			t.Errorf("%s: expected %s, got %s", s.Components[j].Name, status, s.Components[j].Status)
+		}
+	}
+
+	if len(s.Incidents) != 1 {
+		t.Fatalf("expected 1 incident, got %+v", s.Incidents)
+	}
+	i := s.Incidents[0]
+	if i.Status != "identified" || i.Impact != "major" || !i.IsActive || len(i.IncidentUpdates) != 2 {
+		t.Errorf("unexpected incident %+v", i)
+	}
+	if u := i.IncidentUpdates[0]; u.Status != "identified" || !strings.Contains(u.Body, "load balancer") {
+		t.Errorf("expected the latest update first, got %+v", u)
+	}
+
+	if len(s.Maintenances) != 2 || !s.UnderMaintenance("Dashboard", time.Date(2019, 6, 3, 9, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected maintenances %+v", s.Maintenances)
+	}
+	if m := s.Maintenances[1]; m.Status != "scheduled" || !m.Upcoming(providerEnd) {
+		t.Errorf("unexpected maintenance %+v", m)
+	}
+}
+
+func TestInstatusProvider(t *testing.T) {
+	srv := providerServer(t, "instatus")
+	defer srv.Close()
+
+	c, _ := testClient(srv.URL, 0)
+	s, err := InstatusProvider{URL: srv.URL, Client: c}.GetService("linear", providerStart, providerEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Provider != ProviderInstatus || s.StatusPageURL != "https://status.linear.app" || s.Indicator != "critical" {
+		t.Errorf("unexpected service %+v", s)
+	}
+	want := map[string]string{
+		"Web app":      "operational",
+		"Sync":         "degraded_performance",
+		"API":          "operational",
+		"Integrations": "major_outage",
+	}
+	if len(s.Components) != len(want) {
+		t.Fatalf("expected the group to be left out, got %+v", s.Components)
+	}
+	for _, comp := range s.Components {
+		if want[comp.Name] != comp.Status {
+			t.Errorf("%s: expected %s, got %s", comp.Name, want[comp.Name], comp.Status)
+		}
+	}
+
+	if len(s.Incidents) != 1 {
+		t.Fatalf("expected the incident of June 3 only, got %+v", s.Incidents)
+	}
+	if i := s.Incidents[0]; i.Status != "monitoring" || i.Impact != "minor" || i.Shortlink == "" || len(i.IncidentUpdates) != 1 {
+		t.Errorf("unexpected incident %+v", i)
+	}
+
+	if len(s.Maintenances) != 1 {
+		t.Fatalf("expected 1 maintenance, got %+v", s.Maintenances)
+	}
+	if m := s.Maintenances[0]; m.Status != "scheduled" || m.ScheduledUntil.Sub(m.ScheduledFor) != 90*time.Minute {
+		t.Errorf("unexpected maintenance %+v", m)
+	}
+}
+
+func TestCachetProvider(t *testing.T) {
+	srv := providerServer(t, "cachet")
+	defer srv.Close()
+
+	// the third page of incidents is missing, so the second, reaching back before the
+	// start day, must be the last one asked for
+	c, _ := testClient(srv.URL, 0)
+	s, err := CachetProvider{URL: srv.URL, Client: c}.GetService("acme", providerStart, providerEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Provider != ProviderCachet || s.Indicator != "major" {
+		t.Errorf("unexpected service %+v", s)
+	}
+	if len(s.Components) != 2 || s.Components[0].Status != "partial_outage" || s.Components[1].Status != "operational" {
+		t.Errorf("expected the enabled components of both pages, got %+v", s.Components)
+	}
+
+	if len(s.Incidents) != 2 {
+		t.Fatalf("expected 2 incidents, got %+v", s.Incidents)
+	}
+	failing := s.Incidents[0]
+	if failing.Status != "identified" || failing.Impact != "major" || !failing.IsActive {
+		t.Errorf("unexpected incident %+v", failing)
+	}
+	if !failing.CreatedAt.Equal(time.Date(2019, 6, 3, 9, 15, 0, 0, time.UTC)) {
+		t.Errorf("expected the incident to start when it occurred, got %v", failing.CreatedAt)
+	}
+	statuses := []string{"identified", "investigating"}
+	if len(failing.IncidentUpdates) != len(statuses) {
+		t.Fatalf("expected the update and the first message, got %+v", failing.IncidentUpdates)
+	}
+	for j, status := range statuses {
+		if failing.IncidentUpdates[j].Status != status {
+			t.Errorf("update %d: expected %s, got %+v", j, status, failing.IncidentUpdates[j])
+		}
+	}
+	// update 11 of incident 11 and its first message are told apart
+	if ids := [2]string{failing.IncidentUpdates[0].ID, failing.IncidentUpdates[1].ID}; ids != [2]string{"11", "incident-11"} {
+		t.Errorf("expected distinct update IDs, got %v", ids)
+	}
+
+	slow := s.Incidents[1]
+	if slow.Status != "resolved" || slow.IsActive || len(slow.IncidentUpdates) != 1 || slow.IncidentUpdates[0].Status != "resolved" {
+		t.Errorf("unexpected incident %+v", slow)
+	}
+
+	if len(s.Maintenances) != 1 || s.Maintenances[0].Name != "Database upgrade" || s.Maintenances[0].ScheduledFor.IsZero() {
+		t.Errorf("expected the upcoming scheduled incident as a maintenance, got %+v", s.Maintenances)
+	}
+}
+
+func TestProviderConfig(t *testing.T) {
+	if _, err := NewProvider("statuspage", "https://example.com"); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+	if _, err := NewProvider(ProviderCachet, " "); err == nil {
+		t.Error("expected an error for a missing url")
+	}
+
+	cfg := &Config{Services: []ServiceConfig{
+		{Name: "acme", Provider: "cachet", URL: "https://status.acme.test/"},
+		{Name: "github"},
+	}}
+	if p, ok := cfg.Provider("acme").(CachetProvider); !ok || p.URL != "https://status.acme.test" {
+		t.Errorf("unexpected provider %+v", cfg.Provider("acme"))
+	}
+	if cfg.Provider("github") != nil {
+		t.Error("expected github to be read from the backend")
+	}
+}
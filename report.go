@@ -2,20 +2,38 @@ package frain
 
 import (
 	"fmt"
-	"os"
+	"io"
 	"strings"
-	"text/tabwriter"
 	"time"
 
-	wordwrap "github.com/mitchellh/go-wordwrap"
-
 	"github.com/fatih/color"
 )
 
 // Report is an interface implemented by types that generates report in different formats.
 type Report interface {
-	Incidents(bool, bool)
-	All(bool, bool)
+	Incidents(io.Writer, Options) error
+	Components(io.Writer, Options) error
+	All(io.Writer, Options) error
+}
+
+// Options controls how a report is rendered
+type Options struct {
+	// Quiet displays just a summary
+	Quiet bool
+	// Full displays the full version of incident descriptions
+	Full bool
+	// Now is the time relative dates are computed from, the current time when zero
+	Now time.Time
+	// Width is the number of terminal columns the report may take, 0 when unknown
+	Width int
+}
+
+func (o Options) now() time.Time {
+	if o.Now.IsZero() {
+		return time.Now()
+	}
+
+	return o.Now
 }
 
 // Text is a construct to display the page information in text
@@ -31,80 +49,126 @@ var (
 	bold     = color.New(color.Bold)
 )
 
-const maxWidth = 40
+const (
+	// maxWidth is the width of incident descriptions when the terminal width is unknown
+	maxWidth = 40
+
+	minDescWidth = 20
+	minNameWidth = 10
+)
 
 // Incidents implements the Report interface
-func (t Text) Incidents(quiet, full bool) {
-	if quiet {
+func (t Text) Incidents(w io.Writer, opts Options) error {
+	ew := &errWriter{w: w}
+	if opts.Quiet {
 		n := 0
-		t1 := time.Now()
+		t1 := opts.now()
 		for _, i := range t.Data.Service.Incidents {
 			t2 := i.CreatedAt
 			if t1.Day() == t2.Day() && t1.Month() == t2.Month() && t1.Year() == t2.Year() {
 				n++
 			}
 		}
-		fmt.Printf("%d incident(s) reported today.\n", n)
-		return
+		fmt.Fprintf(ew, "%d incident(s) reported today.\n", n)
+		return ew.err
 	}
 
-	w := new(tabwriter.Writer)
-	printIncidents(w, t.Data.Service.Incidents, full)
+	printIncidents(ew, t.Data.Service.Incidents, opts.Full, opts.now(), opts.Width)
+	return ew.err
+}
+
+// Components implements the Report interface
+func (t Text) Components(w io.Writer, opts Options) error {
+	ew := &errWriter{w: w}
+	service := t.Data.Service
+	titleService := fmt.Sprintf("%s Services", title(service.Name))
+	if opts.Quiet {
+		op, numC := countOperational(service.Components)
+		fmt.Fprintf(ew, "%s: %d/%d component(s) are operational.\n", titleService, op, numC)
+		return ew.err
+	}
+
+	bold.Fprintln(ew, titleService)
+	printComponents(ew, service.Components, opts.Width)
+	return ew.err
 }
 
 // All implements the Report interface
-func (t Text) All(quiet, full bool) {
-	w := new(tabwriter.Writer)
+func (t Text) All(w io.Writer, opts Options) error {
+	ew := &errWriter{w: w}
 	service := t.Data.Service
 
+	titleService := fmt.Sprintf("%s Services", title(service.Name))
+	if opts.Quiet {
+		return summarize(ew, titleService, service.Components, service.Incidents)
+	}
+
+	bold.Fprintln(ew, titleService)
+	printComponents(ew, service.Components, opts.Width)
+	fmt.Fprintln(ew)
+	printIncidents(ew, service.Incidents, opts.Full, opts.now(), opts.Width)
+	return ew.err
+}
+
+// title prettifies a service name e.g. google_cloud becomes "Google cloud"
+func title(name string) string {
 	sb := strings.Builder{}
-	words := strings.Split(service.Name, "_")
+	words := strings.Split(name, "_")
 	if len(words) > 0 {
 		words[0] = strings.Title(words[0])
 	}
-	for _, w := range words {
-		sb.Write([]byte(w))
-		sb.Write([]byte(" "))
+	for _, word := range words {
+		sb.WriteString(word)
+		sb.WriteString(" ")
 	}
-	name := strings.TrimSpace(sb.String())
 
-	titleService := fmt.Sprintf("%s Services", name)
-	if quiet {
-		summarize(titleService, service.Components, service.Incidents)
-		return
+	return strings.TrimSpace(sb.String())
+}
+
+// errWriter remembers the first error returned by the underlying writer so that the
+// rendering code does not have to check every single write
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (e *errWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
 	}
 
-	bold.Println(titleService)
-	printComponents(w, service.Components)
-	fmt.Println()
-	printIncidents(w, service.Incidents, full)
+	n, err := e.w.Write(p)
+	e.err = err
+	return n, err
 }
 
-func summarize(title string, components []Component, incidents []Incident) error {
+func countOperational(components []Component) (int, int) {
 	op := 0
 	numC := 0
 	for _, c := range components {
-		if c.Status == "operational" {
+		if ParseComponentStatus(c.Status) == ComponentOperational {
 			op++
 		}
 		numC++
 	}
 
-	fmt.Printf("%s: %d/%d component(s) are operational. %d incident(s) reported.\n",
+	return op, numC
+}
+
+func summarize(w io.Writer, title string, components []Component, incidents []Incident) error {
+	op, numC := countOperational(components)
+	_, err := fmt.Fprintf(w, "%s: %d/%d component(s) are operational. %d incident(s) reported.\n",
 		title,
 		op,
 		numC,
 		len(incidents),
 	)
 
-	return nil
+	return err
 }
 
-func printComponents(w *tabwriter.Writer, comps []Component) {
-	colComponents := "\nCOMPONENT NAME\tSTATUS"
-	w.Init(os.Stdout, 0, 8, 2, '\t', tabwriter.AlignRight)
-
-	titleBar.Fprint(w, colComponents)
+func printComponents(out io.Writer, comps []Component, width int) {
+	t := &table{header: []string{"COMPONENT NAME", "STATUS"}}
 	for _, c := range comps {
 		words := strings.Split(c.Status, "_")
 		sb := strings.Builder{}
@@ -112,46 +176,56 @@ func printComponents(w *tabwriter.Writer, comps []Component) {
 			sb.WriteString(strings.Title(word))
 			sb.WriteString(" ")
 		}
-		status := strings.TrimSpace(sb.String())
+		name := strings.Title(c.Name)
+		if c.IsProbe() {
+			// probes are named after host names
+			name = c.Name
+		}
+		t.add(name, Render(strings.TrimSpace(sb.String())))
+	}
 
-		fmt.Fprint(w, fmt.Sprintf("\n%s\t%s", strings.Title(c.Name), render(status)))
+	// names give way to the status on narrow terminals
+	if widths := t.widths(); width > 0 && widths[0]+columnGap+widths[1] > width {
+		nameWidth := width - columnGap - widths[1]
+		if nameWidth < minNameWidth {
+			nameWidth = minNameWidth
+		}
+		for _, row := range t.rows {
+			row[0] = truncate(row[0], nameWidth)
+		}
 	}
-	fmt.Fprintln(w)
-	w.Flush()
+
+	fmt.Fprintln(out)
+	t.write(out)
 
 	if len(comps) == 0 {
-		fmt.Println("No component reports")
+		fmt.Fprintln(out, "No component reports")
 	}
 }
 
-func printIncidents(w *tabwriter.Writer, inc []Incident, full bool) {
-	colIncidents := "\nDATE\tTIME\tIMPACT\tUPDATED\tDESCRIPTION\tSTATUS\t"
-
-	w.Init(os.Stdout, 0, 8, 2, '\t', tabwriter.AlignRight)
-	bold.Println("Incident History")
+func printIncidents(out io.Writer, inc []Incident, full bool, now time.Time, width int) {
+	bold.Fprintln(out, "Incident History")
 
 	n := len(inc)
 	if n == 0 {
-		fmt.Println("No incident reports")
+		fmt.Fprintln(out, "No incident reports")
 		return
 	}
 
-	titleBar.Fprint(w, colIncidents)
-
+	t := &table{header: []string{"DATE", "TIME", "IMPACT", "UPDATED", "DESCRIPTION", "STATUS"}}
+	var descriptions []string
 	for j := n - 1; j >= 0; j-- {
 		i := inc[j]
-		elapsed, _ := TimeAgo(i.UpdatedAt, time.Now())
+		elapsed, _ := TimeAgo(i.UpdatedAt, now)
 		if elapsed == "0 seconds ago" {
 			elapsed = "     -"
 		}
 
-		description := "-"
-		for _, x := range i.IncidentUpdates {
-			if i.Status == x.Status {
-				description = x.Body
-				break
-			}
+		description := incidentDescription(i)
+		if description == "" {
+			description = "-"
 		}
+		descriptions = append(descriptions, description)
 
 		dte := fmt.Sprintf("%s %d, %d",
 			i.CreatedAt.Month(),
@@ -165,95 +239,78 @@ func printIncidents(w *tabwriter.Writer, inc []Incident, full bool) {
 			i.CreatedAt.Second(),
 		)
 
-		desc := wrap(description, maxWidth)
-		n := len(desc)
+		t.add(dte, tme, strings.Title(i.Impact), elapsed, "", Render(strings.Title(i.Status)))
+	}
+
+	descWidth := descriptionWidth(t.widths(), width)
+	rows := t.rows
+	t.rows = nil
+	for j, row := range rows {
+		desc := wrap(descriptions[j], descWidth)
 		if !full {
-			others := false
-			if n > 1 {
-				others = true
-			}
-			desc[0] = pad(desc[0], maxWidth, others)
+			desc[0] = pad(desc[0], descWidth, len(desc) > 1)
 		}
 
-		fmt.Fprint(
-			w,
-			fmt.Sprintf("\n%s\t%s\t%s\t%s\t%s\t%s\t",
-				dte,
-				tme,
-
-				strings.Title(i.Impact),
-				elapsed,
-				desc[0],
-				render(strings.Title(i.Status)),
-			),
-		)
+		row[4] = desc[0]
+		t.add(row...)
 		if full {
-			n := len(desc)
-			for i := 1; i < n; i++ {
-				fmt.Fprint(w, "\n\t\t\t\t", desc[i], "\t\t")
+			for _, line := range desc[1:] {
+				t.add("", "", "", "", line, "")
 			}
 		}
 	}
-	fmt.Fprintln(w)
-	w.Flush()
+
+	fmt.Fprintln(out)
+	t.write(out)
 }
 
-func render(status string) string {
-	// incident status updates have no underscore whereas component status updates does
-	var r = color.New()
-	s := strings.Split(strings.ToLower(status), " ")
-	switch s[0] {
-	case "operational", "resolved", "postmortem":
-		r.Add(color.FgGreen)
-	case "partial", "under", "investigating", "identified": // partial_outage, under_maintenance
-		r.Add(color.FgYellow)
-	case "degraded", "major", "critical": // degraded_performance, major_outage
-		r.Add(color.FgRed)
-	default:
-		r.Add(color.FgWhite)
+// descriptionWidth spreads the width of the terminal across the incident table. The
+// description takes whatever the other columns leave, but no less than minDescWidth.
+// Without a known width it is maxWidth columns wide.
+func descriptionWidth(widths []int, width int) int {
+	if width <= 0 {
+		return maxWidth
 	}
 
-	return r.Sprint(status)
+	rest := width
+	for i, n := range widths {
+		if i != 4 {
+			rest -= n + columnGap
+		}
+	}
+	if rest < minDescWidth {
+		return minDescWidth
+	}
+
+	return rest
 }
 
-func wrap(s string, width int) []string {
-	if width < 0 {
-		return []string{"-"}
+// incidentDescription returns the body of the update matching the current status of the incident
+func incidentDescription(i Incident) string {
+	for _, x := range i.IncidentUpdates {
+		if i.Status == x.Status {
+			return x.Body
+		}
 	}
 
-	s = wordwrap.WrapString(s, uint(width))
-	words := strings.Split(s, "\n")
-	n := len(words)
-	if n == 0 {
-		return []string{"-"}
-	}
+	return ""
+}
 
-	return words
+// Render colours a status according to its severity
+func Render(status string) string {
+	return color.New(statusColour(status)).Sprint(status)
 }
 
-// This pads any string s with three dots ('.') for a given pad length
+// This pads any string s with three dots ('.') for a given pad length, measured in
+// terminal columns
 func pad(s string, padLength int, others bool) string {
-	if n := len(s); padLength <= n || (!others && n < padLength) {
+	if n := displayWidth(s); padLength < n || (!others && n <= padLength) {
 		return s
 	}
 
-	s += strings.Repeat(".", padLength)
-	s = s[:padLength]
-	dots := 0
-	n := len(s)
-
-	for j := n - 1; j >= 0; j-- {
-		if s[j] != '.' {
-			break
-		}
-		dots++
-	}
-
-	if dots < 3 {
-		s = s[:padLength-3] + "..."
-	} else {
-		s = s[:padLength-dots+3]
+	if displayWidth(s)+3 <= padLength {
+		return s + "..."
 	}
 
-	return s
+	return truncate(s+"...", padLength)
 }
@@ -0,0 +1,185 @@
+package frain
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Run "go test -run TestTextGolden -update" to rewrite the golden files after an
+// intended change of the text report, and review the diff before committing it.
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// goldenNow is the reference time of every golden report
+var goldenNow = time.Date(2019, 6, 3, 12, 0, 0, 0, time.UTC)
+
+func goldenServices() map[string]*Service {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2019, 6, day, hour, min, 0, 0, time.UTC)
+	}
+
+	return map[string]*Service{
+		"typical": {
+			Name: "github",
+			Components: []Component{
+				{Name: "git operations", Status: "operational"},
+				{Name: "API Requests", Status: "operational"},
+				{Name: "Webhooks", Status: "degraded_performance"},
+				{Name: "GitHub Pages", Status: "partial_outage"},
+				{Name: "Actions", Status: "major_outage"},
+				{Name: "Packages", Status: "under_maintenance"},
+			},
+			Incidents: []Incident{
+				{
+					Name: "Delayed webhook deliveries", Impact: "minor", Status: "monitoring",
+					CreatedAt: at(3, 8, 5), UpdatedAt: at(3, 9, 30),
+					IncidentUpdates: []IncidentUpdate{
+						{Status: "monitoring", Body: "A fix has been deployed."},
+						{Status: "investigating", Body: "We are investigating delayed webhooks."},
+					},
+				},
+				{
+					Name: "Increased API error rates", Impact: "major", Status: "resolved",
+					CreatedAt: at(1, 14, 10), UpdatedAt: at(1, 15, 45),
+					IncidentUpdates: []IncidentUpdate{
+						{Status: "resolved", Body: "This incident has been resolved."},
+					},
+				},
+				{
+					Name: "Pages builds failing", Impact: "critical", Status: "investigating",
+					CreatedAt: at(3, 11, 59), UpdatedAt: goldenNow,
+				},
+			},
+		},
+		"empty": {
+			Name: "status_hub",
+		},
+		"long": {
+			Name: "google_cloud",
+			Components: []Component{
+				{Name: "Compute Engine", Status: "operational"},
+			},
+			Incidents: []Incident{
+				{
+					Name: "Networking issues", Impact: "major", Status: "identified",
+					CreatedAt: at(2, 22, 0), UpdatedAt: at(3, 1, 15),
+					IncidentUpdates: []IncidentUpdate{{
+						Status: "identified",
+						Body: "We have identified the cause of elevated packet loss affecting " +
+							"Compute Engine instances in us-east1 and europe-west2 and are rolling out " +
+							"a mitigation. The next update will be provided within two hours.",
+					}},
+				},
+				{
+					Name: "Console slowness", Impact: "minor", Status: "resolved",
+					CreatedAt: at(1, 6, 0), UpdatedAt: at(1, 7, 0),
+					IncidentUpdates: []IncidentUpdate{{
+						Status: "resolved",
+						Body:   "Supercalifragilisticexpialidocious-length-identifiers-do-not-wrap-nicely-at-all.",
+					}},
+				},
+			},
+		},
+		"multibyte": {
+			Name: "zürich_cdn",
+			Components: []Component{
+				{Name: "東京 edge", Status: "operational"},
+				{Name: "Zürich edge", Status: "partial_outage"},
+				{Name: "São Paulo edge", Status: "operational"},
+			},
+			Incidents: []Incident{
+				{
+					Name: "Latence élevée", Impact: "minor", Status: "investigating",
+					CreatedAt: at(3, 10, 0), UpdatedAt: at(3, 10, 30),
+					IncidentUpdates: []IncidentUpdate{{
+						Status: "investigating",
+						Body:   "東京のエッジで遅延が発生しています。Café ☕ traffic is being rerouted via Zürich.",
+					}},
+				},
+			},
+		},
+	}
+}
+
+var goldenModes = []struct {
+	name   string
+	render func(Report, io.Writer, Options) error
+	opts   Options
+}{
+	{"all", Report.All, Options{}},
+	{"all_quiet", Report.All, Options{Quiet: true}},
+	{"all_full", Report.All, Options{Full: true}},
+	{"all_width60", Report.All, Options{Width: 60}},
+	{"all_full_width72", Report.All, Options{Full: true, Width: 72}},
+	{"all_width120", Report.All, Options{Width: 120}},
+	{"incidents", Report.Incidents, Options{}},
+	{"incidents_quiet", Report.Incidents, Options{Quiet: true}},
+	{"components", Report.Components, Options{}},
+	{"components_quiet", Report.Components, Options{Quiet: true}},
+}
+
+func TestTextGolden(t *testing.T) {
+	noColor := color.NoColor
+	defer func() { color.NoColor = noColor }()
+
+	for fixture, service := range goldenServices() {
+		for _, mode := range goldenModes {
+			for _, colour := range []bool{false, true} {
+				// colour output is only kept for the fixture exercising every status
+				if colour && fixture != "typical" {
+					continue
+				}
+
+				name := fixture + "_" + mode.name
+				if colour {
+					name += "_colour"
+				}
+
+				t.Run(name, func(t *testing.T) {
+					color.NoColor = !colour
+
+					opts := mode.opts
+					opts.Now = goldenNow
+					var buf bytes.Buffer
+					report := Text{Data: &Page{Name: service.Name, Service: service}}
+					if err := mode.render(report, &buf, opts); err != nil {
+						t.Fatal(err)
+					}
+
+					checkGolden(t, filepath.Join("testdata", "golden", name+".txt"), buf.Bytes())
+				})
+			}
+		}
+	}
+}
+
+// checkGolden compares got with the content of the golden file at path, rewriting the
+// file instead when the -update flag is set
+func checkGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s (run go test -update to accept it)\n--- got\n%s\n--- want\n%s", path, got, want)
+	}
+}
@@ -1,7 +1,11 @@
 package frain
 
 import (
+	"bytes"
+	"errors"
 	"testing"
+
+	"github.com/fatih/color"
 )
 
 func TestPad(t *testing.T) {
@@ -22,3 +26,40 @@ func TestPad(t *testing.T) {
 		}
 	}
 }
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestTextAll(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	page := &Page{
+		Name: "github",
+		Service: &Service{
+			Name: "github",
+			Components: []Component{
+				{Name: "API Requests", Status: "operational"},
+				{Name: "Webhooks", Status: "partial_outage"},
+			},
+			Incidents: []Incident{{Name: "Webhook delays", Status: "investigating"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := (Text{Data: page}).All(&buf, Options{Quiet: true}); err != nil {
+		t.Fatal(err)
+	}
+	want := "Github Services: 1/2 component(s) are operational. 1 incident(s) reported.\n"
+	if got := buf.String(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if err := (Text{Data: page}).All(failingWriter{}, Options{}); err == nil {
+		t.Error("expected write error to be returned")
+	}
+}
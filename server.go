@@ -1,12 +1,9 @@
 package frain
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
-	"os"
 	"strings"
 	"time"
 )
@@ -18,7 +15,8 @@ type Data struct {
 
 // Result is a model to match the `data` JSON tag from frain backend
 type Result struct {
-	Data `json:"data"`
+	Data   `json:"data"`
+	Errors []QueryError `json:"errors"`
 }
 
 // SingleData is a model to match the `getService` JSON tag from frain backend for a single service
@@ -29,6 +27,13 @@ type SingleData struct {
 // SingleResult is a model to match the `data` JSON tag from frain backend for a single service
 type SingleResult struct {
 	SingleData `json:"data"`
+	Errors     []QueryError `json:"errors"`
+}
+
+// QueryError is an error reported by the frain backend in the `errors` JSON tag, e.g. when
+// it fails to validate a query
+type QueryError struct {
+	Message string `json:"message"`
 }
 
 var (
@@ -37,8 +42,14 @@ var (
 )
 
 // GetService sends a POST request to the host server and then returns all information
-// relating to a developer tool to check
+// relating to a developer tool to check. It uses DefaultClient.
 func GetService(name string, startTime, endTime time.Time) (*Service, error) {
+	return DefaultClient.GetService(name, startTime, endTime)
+}
+
+// GetService sends a POST request to the host server and then returns all information
+// relating to a developer tool to check
+func (c *Client) GetService(name string, startTime, endTime time.Time) (*Service, error) {
 	q := fmt.Sprintf(`{"query": "{getService(name:%s)`+
 		`{id, name, statusPageUrl, provider, indicator, isActive, createdAt, updatedAt,`+
 		` components`+
@@ -47,49 +58,89 @@ func GetService(name string, startTime, endTime time.Time) (*Service, error) {
 		`{id, name,impact, status, isActive, createdAt, shortlink, updatedAt, incidentUpdates{id, body, status, createdAt, updatedAt}},`+
 		` highLevelComponents`+
 		`{id, name, status, description}}}"}`, name, parseDate(&startTime), parseDate(&endTime))
-	query := bytes.NewBuffer([]byte(q))
-	host := os.Getenv("FRAIN_HOST")
-	if host == "" {
-		host = "https://frain-server.herokuapp.com/graphql"
+
+	maintenances := make(chan []Maintenance, 1)
+	go func() { maintenances <- c.getMaintenances(name) }()
+
+	var result SingleResult
+	if err := c.query(q, &result); err != nil {
+		return nil, err
+	}
+	if err := queryError(result.Errors); err != nil {
+		return nil, err
 	}
+	result.Service.Maintenances = <-maintenances
 
-	resp, err := http.Post(host, "application/json", query)
+	return &result.Service, nil
+}
+
+// getMaintenances returns the scheduled maintenances of a service. They are asked for on
+// their own, alongside the service, as not every backend knows about them, a failure
+// leaving the service without any. Being optional, they are not worth a retry.
+func (c *Client) getMaintenances(name string) []Maintenance {
+	once := *c
+	once.Retry.Retries = 0
+
+	q := fmt.Sprintf(`{"query": "{getService(name:%s)`+
+		`{scheduledMaintenances`+
+		`{id, name, impact, status, shortlink, scheduledFor, scheduledUntil, createdAt, updatedAt,`+
+		` components{id, name, status}, incidentUpdates{id, body, status, createdAt, updatedAt}}}}"}`, name)
+
+	var result SingleResult
+	if err := once.query(q, &result); err != nil || len(result.Errors) > 0 {
+		return nil
+	}
+
+	return result.Service.Maintenances
+}
+
+// query posts the query to the backend and decodes the response into v
+func (c *Client) query(q string, v interface{}) error {
+	resp, err := c.post([]byte(q))
 	if err != nil {
-		return nil, errHTTPPost
+		return err
 	}
 	defer resp.Body.Close()
 
-	var result SingleResult
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return nil, errJSONDecode
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return errJSONDecode
 	}
 
-	return &result.Service, nil
+	return nil
+}
+
+// queryError turns the errors reported by the backend into one, nil when there is none
+func queryError(errs []QueryError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Message)
+	}
+
+	return fmt.Errorf("Error: the backend failed to answer: %s", strings.Join(msgs, "; "))
 }
 
 func parseDate(t *time.Time) string {
 	return fmt.Sprintf("%04d-%02d-%02d", t.Year(), int(t.Month()), t.Day())
 }
 
-// GetServiceList returns a list of services currently supported by frain
+// GetServiceList returns a list of services currently supported by frain. It uses
+// DefaultClient.
 func GetServiceList() ([]string, error) {
-	query := bytes.NewBuffer([]byte(`{ "query": "{getAllServices {name}}" }`))
-	host := os.Getenv("FRAIN_HOST")
-	if host == "" {
-		host = "https://frain-server.herokuapp.com/graphql"
-	}
-
-	resp, err := http.Post(host, "application/json", query)
-	if err != nil {
-		return nil, errHTTPPost
-	}
-	defer resp.Body.Close()
+	return DefaultClient.GetServiceList()
+}
 
+// GetServiceList returns a list of services currently supported by frain
+func (c *Client) GetServiceList() ([]string, error) {
 	var result Result
-	err = json.NewDecoder(resp.Body).Decode(&result)
-	if err != nil {
-		return nil, errJSONDecode
+	if err := c.query(`{ "query": "{getAllServices {name}}" }`, &result); err != nil {
+		return nil, err
+	}
+	if err := queryError(result.Errors); err != nil {
+		return nil, err
 	}
 
 	var services []string
@@ -0,0 +1,158 @@
+package frain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSnapshotAge is how long snapshots are kept in the snapshot directory, enough to
+// compare with or correlate over the past week even when frain watch records one every
+// minute
+const MaxSnapshotAge = 8 * 24 * time.Hour
+
+// Snapshot records the state of a service at a given point in time
+type Snapshot struct {
+	TakenAt time.Time `json:"takenAt"`
+	Service *Service  `json:"service"`
+}
+
+// DefaultSnapshotDir returns the directory in which frain records the snapshots of the
+// services it fetches
+func DefaultSnapshotDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "frain", "snapshots")
+}
+
+// SaveSnapshot writes a snapshot of the service taken at t to the file at path
+func SaveSnapshot(path string, s *Service, t time.Time) error {
+	data, err := json.MarshalIndent(Snapshot{TakenAt: t, Service: s}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// LoadSnapshot reads the snapshot saved in the file at path
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %v", path, err)
+	}
+	if snap.Service == nil {
+		return nil, fmt.Errorf("snapshot %s contains no service", path)
+	}
+
+	return &snap, nil
+}
+
+// RecordSnapshot stores a snapshot of the service taken at t in dir, discarding the
+// snapshots of that service taken more than MaxSnapshotAge before t
+func RecordSnapshot(dir string, s *Service, t time.Time) error {
+	serviceDir := filepath.Join(dir, strings.ToLower(s.Name))
+	if err := os.MkdirAll(serviceDir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(serviceDir, fmt.Sprintf("%d.json", t.Unix()))
+	if err := SaveSnapshot(path, s, t); err != nil {
+		return err
+	}
+
+	times, err := snapshotTimes(serviceDir)
+	if err != nil {
+		return err
+	}
+	oldest := t.Add(-MaxSnapshotAge).Unix()
+	for _, taken := range times {
+		if taken < oldest {
+			os.Remove(filepath.Join(serviceDir, fmt.Sprintf("%d.json", taken)))
+		}
+	}
+
+	return nil
+}
+
+// FindSnapshot returns the most recent snapshot of the named service recorded in dir
+// no later than before
+func FindSnapshot(dir, name string, before time.Time) (*Snapshot, error) {
+	serviceDir := filepath.Join(dir, strings.ToLower(name))
+	times, err := snapshotTimes(serviceDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	for j := len(times) - 1; j >= 0; j-- {
+		if times[j] <= before.Unix() {
+			return LoadSnapshot(filepath.Join(serviceDir, fmt.Sprintf("%d.json", times[j])))
+		}
+	}
+
+	return nil, fmt.Errorf("no snapshot of %s taken before %s", name, before.Format(time.RFC1123))
+}
+
+// LatestSnapshot returns the most recent snapshot of the named service recorded in dir
+func LatestSnapshot(dir, name string) (*Snapshot, error) {
+	return FindSnapshot(dir, name, time.Now())
+}
+
+func snapshotTimes(dir string) ([]int64, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var times []int64
+	for _, f := range files {
+		ts := strings.TrimSuffix(f.Name(), ".json")
+		if ts == f.Name() {
+			continue
+		}
+		if t, err := strconv.ParseInt(ts, 10, 64); err == nil {
+			times = append(times, t)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+
+	return times, nil
+}
+
+// SnapshotsSince returns the snapshots of the named service recorded in dir from since
+// onwards, the oldest first
+func SnapshotsSince(dir, name string, since time.Time) ([]*Snapshot, error) {
+	serviceDir := filepath.Join(dir, strings.ToLower(name))
+	times, err := snapshotTimes(serviceDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var snaps []*Snapshot
+	for _, t := range times {
+		if t < since.Unix() {
+			continue
+		}
+		snap, err := LoadSnapshot(filepath.Join(serviceDir, fmt.Sprintf("%d.json", t)))
+		if err != nil {
+			return nil, err
+		}
+		snaps = append(snaps, snap)
+	}
+
+	return snaps, nil
+}
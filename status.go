@@ -0,0 +1,210 @@
+package frain
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// ComponentStatus is the status of a service component. Statuses are ordered by
+// severity, so that a greater status is a worse one. Unknown sorts before every known
+// status.
+type ComponentStatus int
+
+// Component statuses from the least to the most severe
+const (
+	ComponentUnknown ComponentStatus = iota
+	ComponentOperational
+	ComponentUnderMaintenance
+	ComponentDegradedPerformance
+	ComponentPartialOutage
+	ComponentMajorOutage
+)
+
+var componentStatuses = []string{
+	ComponentUnknown:             "unknown",
+	ComponentOperational:         "operational",
+	ComponentUnderMaintenance:    "under_maintenance",
+	ComponentDegradedPerformance: "degraded_performance",
+	ComponentPartialOutage:       "partial_outage",
+	ComponentMajorOutage:         "major_outage",
+}
+
+// ParseComponentStatus reads a component status as sent by the backend, e.g.
+// "partial_outage". Case and the separator between words do not matter, so the
+// prettified "Partial Outage" is understood as well.
+func ParseComponentStatus(s string) ComponentStatus {
+	return ComponentStatus(parseEnum(s, componentStatuses))
+}
+
+func (s ComponentStatus) String() string {
+	return enumString(int(s), componentStatuses)
+}
+
+// MarshalText encodes the status by name, e.g. in JSON output
+func (s ComponentStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// IncidentStatus is the status of an incident or scheduled maintenance. Statuses are
+// ordered along the life of an incident, from investigating to postmortem, followed by
+// those of a maintenance. Unknown sorts before every known status.
+type IncidentStatus int
+
+// Incident statuses in the order an incident goes through them
+const (
+	IncidentUnknown IncidentStatus = iota
+	IncidentInvestigating
+	IncidentIdentified
+	IncidentMonitoring
+	IncidentResolved
+	IncidentPostmortem
+	IncidentScheduled
+	IncidentInProgress
+	IncidentVerifying
+	IncidentCompleted
+)
+
+var incidentStatuses = []string{
+	IncidentUnknown:       "unknown",
+	IncidentInvestigating: "investigating",
+	IncidentIdentified:    "identified",
+	IncidentMonitoring:    "monitoring",
+	IncidentResolved:      "resolved",
+	IncidentPostmortem:    "postmortem",
+	IncidentScheduled:     "scheduled",
+	IncidentInProgress:    "in_progress",
+	IncidentVerifying:     "verifying",
+	IncidentCompleted:     "completed",
+}
+
+// ParseIncidentStatus reads an incident status as sent by the backend
+func ParseIncidentStatus(s string) IncidentStatus {
+	return IncidentStatus(parseEnum(s, incidentStatuses))
+}
+
+func (s IncidentStatus) String() string {
+	return enumString(int(s), incidentStatuses)
+}
+
+// MarshalText encodes the status by name, e.g. in JSON output
+func (s IncidentStatus) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Resolved reports whether the incident or maintenance is over
+func (s IncidentStatus) Resolved() bool {
+	return s == IncidentResolved || s == IncidentPostmortem || s == IncidentCompleted
+}
+
+// Impact is the impact of an incident. Impacts are ordered by severity and Unknown
+// sorts before every known impact.
+type Impact int
+
+// Incident impacts from the least to the most severe
+const (
+	ImpactUnknown Impact = iota
+	ImpactNone
+	ImpactMaintenance
+	ImpactMinor
+	ImpactMajor
+	ImpactCritical
+)
+
+var impacts = []string{
+	ImpactUnknown:     "unknown",
+	ImpactNone:        "none",
+	ImpactMaintenance: "maintenance",
+	ImpactMinor:       "minor",
+	ImpactMajor:       "major",
+	ImpactCritical:    "critical",
+}
+
+// ParseImpact reads an incident impact as sent by the backend
+func ParseImpact(s string) Impact {
+	return Impact(parseEnum(s, impacts))
+}
+
+func (i Impact) String() string {
+	return enumString(int(i), impacts)
+}
+
+// MarshalText encodes the impact by name, e.g. in JSON output
+func (i Impact) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// Indicator is the overall status of a service as summarised by its status page.
+// Indicators are ordered by severity and Unknown sorts before every known indicator.
+type Indicator int
+
+// Service indicators from the least to the most severe
+const (
+	IndicatorUnknown Indicator = iota
+	IndicatorNone
+	IndicatorMaintenance
+	IndicatorMinor
+	IndicatorMajor
+	IndicatorCritical
+)
+
+var indicators = []string{
+	IndicatorUnknown:     "unknown",
+	IndicatorNone:        "none",
+	IndicatorMaintenance: "maintenance",
+	IndicatorMinor:       "minor",
+	IndicatorMajor:       "major",
+	IndicatorCritical:    "critical",
+}
+
+// ParseIndicator reads a service indicator as sent by the backend
+func ParseIndicator(s string) Indicator {
+	return Indicator(parseEnum(s, indicators))
+}
+
+func (i Indicator) String() string {
+	return enumString(int(i), indicators)
+}
+
+// MarshalText encodes the indicator by name, e.g. in JSON output
+func (i Indicator) MarshalText() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+// parseEnum returns the index of s among names, or 0 (unknown) when it is not one of them
+func parseEnum(s string, names []string) int {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.NewReplacer(" ", "_", "-", "_").Replace(s)
+	for i, name := range names {
+		if i > 0 && name == s {
+			return i
+		}
+	}
+
+	return 0
+}
+
+func enumString(i int, names []string) string {
+	if i < 0 || i >= len(names) {
+		return names[0]
+	}
+
+	return names[i]
+}
+
+// statusColour returns the colour a status, impact or indicator is rendered with, a
+// component status taking that of its impact
+func statusColour(s string) color.Attribute {
+	if status := ParseComponentStatus(s); status != ComponentUnknown {
+		return impactColour(componentImpacts[status])
+	}
+
+	switch ParseIncidentStatus(s) {
+	case IncidentResolved, IncidentPostmortem, IncidentCompleted:
+		return color.FgGreen
+	case IncidentInvestigating, IncidentIdentified, IncidentInProgress, IncidentVerifying:
+		return color.FgYellow
+	}
+
+	return impactColour(ParseImpact(s))
+}
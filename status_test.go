@@ -0,0 +1,115 @@
+package frain
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestParseComponentStatus(t *testing.T) {
+	tests := []struct {
+		s    string
+		want ComponentStatus
+	}{
+		{"operational", ComponentOperational},
+		{"partial_outage", ComponentPartialOutage},
+		{"Partial Outage", ComponentPartialOutage},
+		{"MAJOR_OUTAGE", ComponentMajorOutage},
+		{"degraded-performance", ComponentDegradedPerformance},
+		{"exploded", ComponentUnknown},
+		{"", ComponentUnknown},
+		{"unknown", ComponentUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := ParseComponentStatus(tt.s); got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.s, tt.want, got)
+		}
+	}
+}
+
+func TestEnumStrings(t *testing.T) {
+	for s := ComponentUnknown; s <= ComponentMajorOutage; s++ {
+		if got := ParseComponentStatus(s.String()); got != s {
+			t.Errorf("component status %v does not round trip, got %v", s, got)
+		}
+	}
+	for s := IncidentUnknown; s <= IncidentCompleted; s++ {
+		if got := ParseIncidentStatus(s.String()); got != s {
+			t.Errorf("incident status %v does not round trip, got %v", s, got)
+		}
+	}
+	for i := ImpactUnknown; i <= ImpactCritical; i++ {
+		if got := ParseImpact(i.String()); got != i {
+			t.Errorf("impact %v does not round trip, got %v", i, got)
+		}
+	}
+	for i := IndicatorUnknown; i <= IndicatorCritical; i++ {
+		if got := ParseIndicator(i.String()); got != i {
+			t.Errorf("indicator %v does not round trip, got %v", i, got)
+		}
+	}
+
+	if s := ComponentStatus(42).String(); s != "unknown" {
+		t.Errorf("expected an out of range status to be unknown, got %v", s)
+	}
+}
+
+func TestSeverityOrder(t *testing.T) {
+	impacts := []Impact{ParseImpact("minor"), ParseImpact("critical"), ParseImpact("none"), ParseImpact("major")}
+	sort.Slice(impacts, func(a, b int) bool { return impacts[a] > impacts[b] })
+
+	want := []Impact{ImpactCritical, ImpactMajor, ImpactMinor, ImpactNone}
+	for i := range want {
+		if impacts[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, impacts)
+		}
+	}
+
+	if !(ComponentMajorOutage > ComponentPartialOutage && ComponentPartialOutage > ComponentOperational) {
+		t.Error("component statuses are not ordered by severity")
+	}
+	if !(IndicatorCritical > IndicatorMinor && IndicatorMinor > IndicatorNone) {
+		t.Error("indicators are not ordered by severity")
+	}
+}
+
+func TestIncidentStatusResolved(t *testing.T) {
+	for _, s := range []string{"resolved", "postmortem", "completed"} {
+		if !ParseIncidentStatus(s).Resolved() {
+			t.Errorf("expected %s to be resolved", s)
+		}
+	}
+	for _, s := range []string{"investigating", "monitoring", "in_progress", "whatever"} {
+		if ParseIncidentStatus(s).Resolved() {
+			t.Errorf("expected %s not to be resolved", s)
+		}
+	}
+}
+
+func TestStatusColour(t *testing.T) {
+	tests := []struct {
+		s    string
+		want color.Attribute
+	}{
+		{"Operational", color.FgGreen},
+		{"Under Maintenance", color.FgYellow},
+		{"Degraded Performance", color.FgYellow},
+		{"Partial Outage", color.FgRed},
+		{"Major Outage", color.FgRed},
+		{"Investigating", color.FgYellow},
+		{"Postmortem", color.FgGreen},
+		{"Monitoring", color.FgWhite},
+		{"Critical", color.FgRed},
+		{"Minor", color.FgYellow},
+		{"None", color.FgGreen},
+		{"Exploded", color.FgWhite},
+	}
+
+	for _, tt := range tests {
+		if got := statusColour(tt.s); got != tt.want {
+			t.Errorf("%q: expected %v, got %v", tt.s, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package frain
+
+import (
+	"time"
+)
+
+// StatusIOProvider reads a service from the public status API of a Status.io page,
+// https://api.status.io/1.0/status/ followed by the ID of the page. The API only lists
+// the unresolved incidents.
+type StatusIOProvider struct {
+	URL string
+	// Client fetches the page, DefaultClient when nil
+	Client *Client
+}
+
+type statusIOPage struct {
+	Result struct {
+		StatusOverall statusIOStatus `json:"status_overall"`
+		Status        []struct {
+			ID string `json:"id"`
+			statusIOStatus
+			Name string `json:"name"`
+		} `json:"status"`
+		Incidents   []statusIOEvent `json:"incidents"`
+		Maintenance struct {
+			Active   []statusIOEvent `json:"active"`
+			Upcoming []statusIOEvent `json:"upcoming"`
+		} `json:"maintenance"`
+	} `json:"result"`
+}
+
+type statusIOStatus struct {
+	Updated    time.Time `json:"updated"`
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code"`
+}
+
+// statusIOEvent is an incident or a maintenance
+type statusIOEvent struct {
+	ID           string    `json:"_id"`
+	Name         string    `json:"name"`
+	DatetimeOpen time.Time `json:"datetime_open"`
+	PlannedStart time.Time `json:"datetime_planned_start"`
+	PlannedEnd   time.Time `json:"datetime_planned_end"`
+	Messages     []struct {
+		ID         string    `json:"_id"`
+		Details    string    `json:"details"`
+		State      int       `json:"state"`
+		StatusCode int       `json:"status"`
+		Datetime   time.Time `json:"datetime"`
+	} `json:"messages"`
+	ComponentsAffected []struct {
+		ID   string `json:"_id"`
+		Name string `json:"name"`
+	} `json:"components_affected"`
+}
+
+// Status.io status codes, shared by components, incident messages and the page
+var (
+	statusIOComponentStatuses = map[int]ComponentStatus{
+		100: ComponentOperational,
+		200: ComponentUnderMaintenance,
+		300: ComponentDegradedPerformance,
+		400: ComponentPartialOutage,
+		500: ComponentMajorOutage,
+		600: ComponentMajorOutage, // security event
+	}
+	statusIOStates = map[int]IncidentStatus{
+		100: IncidentInvestigating,
+		200: IncidentIdentified,
+		300: IncidentMonitoring,
+	}
+)
+
+// GetService implements the Provider interface
+func (p StatusIOProvider) GetService(name string, startTime, endTime time.Time) (*Service, error) {
+	var page statusIOPage
+	if err := getJSON(p.Client, p.URL, &page); err != nil {
+		return nil, err
+	}
+
+	r := page.Result
+	s := &Service{
+		Name:      name,
+		Provider:  ProviderStatusIO,
+		Status:    r.StatusOverall.Status,
+		Indicator: statusIOImpact(r.StatusOverall.StatusCode).String(),
+		IsActive:  true,
+		UpdatedAt: r.StatusOverall.Updated,
+	}
+
+	for _, c := range r.Status {
+		s.Components = append(s.Components, Component{
+			ID:          c.ID,
+			ComponentID: c.ID,
+			Name:        c.Name,
+			Status:      statusIOComponentStatuses[c.StatusCode].String(),
+			UpdatedAt:   c.Updated,
+		})
+	}
+
+	for _, e := range r.Incidents {
+		i := Incident{
+			ID:         e.ID,
+			IncidentID: e.ID,
+			Name:       e.Name,
+			Status:     IncidentInvestigating.String(),
+			Impact:     ImpactMinor.String(),
+			IsActive:   true,
+			CreatedAt:  e.DatetimeOpen,
+			UpdatedAt:  e.DatetimeOpen,
+		}
+		i.IncidentUpdates = e.updates(func(state int) string { return statusIOStates[state].String() })
+		for j, m := range e.Messages {
+			if j == 0 || !m.Datetime.Before(i.UpdatedAt) {
+				i.Status = statusIOStates[m.State].String()
+				i.Impact = statusIOImpact(m.StatusCode).String()
+				i.UpdatedAt = m.Datetime
+			}
+		}
+		s.Incidents = append(s.Incidents, i)
+	}
+	s.Incidents = createdBetween(s.Incidents, startTime, endTime)
+
+	for _, e := range r.Maintenance.Active {
+		s.Maintenances = append(s.Maintenances, e.maintenance(IncidentInProgress))
+	}
+	for _, e := range r.Maintenance.Upcoming {
+		s.Maintenances = append(s.Maintenances, e.maintenance(IncidentScheduled))
+	}
+
+	return s, nil
+}
+
+// statusIOImpact maps a status code to the impact of an incident
+func statusIOImpact(code int) Impact {
+	status, ok := statusIOComponentStatuses[code]
+	if !ok {
+		return ImpactUnknown
+	}
+
+	return componentImpacts[status]
+}
+
+// updates returns the messages of the event as updates, the latest first
+func (e statusIOEvent) updates(status func(state int) string) []IncidentUpdate {
+	var updates []IncidentUpdate
+	for _, m := range e.Messages {
+		updates = append(updates, IncidentUpdate{
+			ID:               m.ID,
+			IncidentUpdateID: m.ID,
+			IncidentID:       e.ID,
+			Status:           status(m.State),
+			Body:             m.Details,
+			CreatedAt:        m.Datetime,
+			UpdatedAt:        m.Datetime,
+		})
+	}
+	sortUpdates(updates)
+
+	return updates
+}
+
+func (e statusIOEvent) maintenance(status IncidentStatus) Maintenance {
+	m := Maintenance{
+		ID:             e.ID,
+		Name:           e.Name,
+		Status:         status.String(),
+		Impact:         ImpactMaintenance.String(),
+		ScheduledFor:   e.PlannedStart,
+		ScheduledUntil: e.PlannedEnd,
+	}
+	m.IncidentUpdates = e.updates(func(int) string { return status.String() })
+	if len(m.IncidentUpdates) > 0 {
+		m.CreatedAt = m.IncidentUpdates[len(m.IncidentUpdates)-1].CreatedAt
+		m.UpdatedAt = m.IncidentUpdates[0].CreatedAt
+	}
+	for _, c := range e.ComponentsAffected {
+		m.Components = append(m.Components, Component{ID: c.ID, Name: c.Name, Status: ComponentUnderMaintenance.String()})
+	}
+
+	return m
+}
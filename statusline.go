@@ -0,0 +1,98 @@
+package frain
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Marks summarising the health of a service on a status line
+const (
+	MarkOK       = "✓"
+	MarkWarning  = "⚠"
+	MarkCritical = "✗"
+	MarkUnknown  = "?"
+)
+
+// abbreviations holds the short names of the services shown on a status line
+var abbreviations = map[string]string{
+	"bitbucket":  "BB",
+	"circleci":   "CI",
+	"datadog":    "DD",
+	"fastly":     "FS",
+	"github":     "GH",
+	"mailgun":    "MG",
+	"medium":     "MD",
+	"statuspage": "SP",
+	"twilio":     "TW",
+}
+
+var markColours = map[string]*color.Color{
+	MarkOK:       color.New(color.FgGreen),
+	MarkWarning:  color.New(color.FgYellow),
+	MarkCritical: color.New(color.FgRed),
+	MarkUnknown:  color.New(color.FgWhite),
+}
+
+// Abbreviation returns the short name of a service used on a status line
+func Abbreviation(name string) string {
+	name = strings.ToLower(name)
+	if a, ok := abbreviations[name]; ok {
+		return a
+	}
+
+	var initials []rune
+	for _, w := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		initials = append(initials, []rune(w)[0])
+	}
+	if len(initials) < 2 {
+		initials = []rune(name)
+		if len(initials) > 2 {
+			initials = initials[:2]
+		}
+	}
+
+	return strings.ToUpper(string(initials))
+}
+
+// Mark summarises the health of a service from its indicator and the status of its
+// components. A nil service is marked as unknown.
+func Mark(s *Service) string {
+	if s == nil {
+		return MarkUnknown
+	}
+
+	mark := MarkOK
+	switch indicator := ParseIndicator(s.Indicator); {
+	case indicator >= IndicatorMajor:
+		return MarkCritical
+	case indicator >= IndicatorMaintenance:
+		mark = MarkWarning
+	}
+
+	for _, c := range s.Components {
+		switch status := ParseComponentStatus(c.Status); {
+		case status >= ComponentMajorOutage:
+			return MarkCritical
+		case status > ComponentOperational:
+			mark = MarkWarning
+		}
+	}
+
+	return mark
+}
+
+// StatusLine returns a compact single line indicator such as "GH✓ CI⚠ FS✗" for the named
+// services. Services missing from the map are marked as unknown.
+func StatusLine(names []string, services map[string]*Service, colour bool) string {
+	var parts []string
+	for _, name := range names {
+		mark := Mark(services[strings.ToLower(name)])
+		if colour {
+			mark = markColours[mark].Sprint(mark)
+		}
+		parts = append(parts, Abbreviation(name)+mark)
+	}
+
+	return strings.Join(parts, " ")
+}
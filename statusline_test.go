@@ -0,0 +1,42 @@
+package frain
+
+import (
+	"testing"
+)
+
+func TestStatusLine(t *testing.T) {
+	services := map[string]*Service{
+		"github": {Name: "github", Indicator: "none"},
+		"circleci": {
+			Name:       "circleci",
+			Indicator:  "none",
+			Components: []Component{{Name: "Pipelines", Status: "degraded_performance"}},
+		},
+		"fastly":     {Name: "fastly", Indicator: "major"},
+		"status_hub": {Name: "status_hub", Indicator: "none"},
+	}
+
+	names := []string{"github", "circleci", "fastly", "status_hub", "twilio"}
+	want := "GH✓ CI⚠ FS✗ SH✓ TW?"
+	if got := StatusLine(names, services, false); got != want {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestAbbreviation(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"github", "GH"},
+		{"Pingdom", "PI"},
+		{"google_cloud", "GC"},
+		{"x", "X"},
+	}
+
+	for _, tt := range tests {
+		if got := Abbreviation(tt.name); got != tt.want {
+			t.Errorf("expected %v, got %v", tt.want, got)
+		}
+	}
+}
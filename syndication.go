@@ -0,0 +1,329 @@
+package frain
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Feed formats written by WriteFeed
+const (
+	FeedAtom = "atom"
+	FeedRSS  = "rss"
+)
+
+var (
+	errFeedFormat     = errors.New("unknown feed format, expected atom or rss")
+	errComponentsFeed = errors.New("components cannot be written as a feed, only incidents")
+)
+
+// FeedOptions describes the feed written by WriteFeed
+type FeedOptions struct {
+	// Title of the feed, "frain incidents" when empty
+	Title string
+	// Link is the URL the feed is published at, if any
+	Link string
+	// Now is the time the feed is updated at when it has no entry, the current time
+	// when zero
+	Now time.Time
+	// MaxAge leaves out the entries posted longer than this before Now, none when zero,
+	// so that the updates of an incident created earlier are published as they come
+	MaxAge time.Duration
+}
+
+// feedEntry is an incident update, or an incident without any update, as published in
+// a feed
+type feedEntry struct {
+	guid    string
+	title   string
+	link    string
+	body    string
+	impact  string
+	updated time.Time
+}
+
+// WriteFeed writes the incidents of services as one Atom or RSS feed with an entry per
+// incident update, the latest first, so that feed readers show every update once. The
+// GUID of an entry is built from the service, incident and update IDs and so stays the
+// same from one fetch to the next. A nil service is skipped.
+func WriteFeed(w io.Writer, format string, services []*Service, opts FeedOptions) error {
+	if opts.Title == "" {
+		opts.Title = "frain incidents"
+	}
+	updated := opts.Now
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	entries := feedEntries(services)
+	if opts.MaxAge > 0 {
+		cutoff := updated.Add(-opts.MaxAge)
+		var recent []feedEntry
+		for _, e := range entries {
+			if !e.updated.Before(cutoff) {
+				recent = append(recent, e)
+			}
+		}
+		entries = recent
+	}
+	if len(entries) > 0 {
+		updated = entries[0].updated
+	}
+
+	var feed interface{}
+	switch strings.ToLower(format) {
+	case FeedAtom:
+		feed = atomOutput(entries, opts, updated)
+	case FeedRSS:
+		feed = rssOutput(entries, opts, updated)
+	default:
+		return errFeedFormat
+	}
+
+	ew := &errWriter{w: w}
+	io.WriteString(ew, xml.Header)
+	enc := xml.NewEncoder(ew)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	io.WriteString(ew, "\n")
+
+	return ew.err
+}
+
+func feedEntries(services []*Service) []feedEntry {
+	var entries []feedEntry
+	for _, s := range services {
+		if s == nil {
+			continue
+		}
+
+		for _, i := range s.Incidents {
+			incidentID := firstOf(i.IncidentID, i.ID)
+			entry := feedEntry{
+				link:   firstOf(i.Shortlink, s.StatusPageURL),
+				impact: i.Impact,
+			}
+
+			if len(i.IncidentUpdates) == 0 {
+				entry.guid = feedGUID(s.Name, incidentID, "")
+				entry.title = feedTitle(s, i, i.Status)
+				entry.updated = i.UpdatedAt
+				entries = append(entries, entry)
+				continue
+			}
+
+			for _, u := range i.IncidentUpdates {
+				e := entry
+				e.guid = feedGUID(s.Name, incidentID, firstOf(u.IncidentUpdateID, u.ID))
+				e.title = feedTitle(s, i, u.Status)
+				e.body = u.Body
+				e.updated = u.CreatedAt
+				entries = append(entries, e)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool { return entries[a].updated.After(entries[b].updated) })
+
+	return entries
+}
+
+// feedTitle titles an entry after its service and incident, led by the status marker
+// that ParseFeed strips to group the updates of an incident
+func feedTitle(s *Service, i Incident, status string) string {
+	return fmt.Sprintf("[%s] %s: %s", strings.Title(strings.Replace(status, "_", " ", -1)), title(s.Name), i.Name)
+}
+
+// feedGUID identifies an incident update, or an incident when update is empty
+func feedGUID(service, incident, update string) string {
+	parts := []string{"urn", "frain", url.QueryEscape(strings.ToLower(service)), url.QueryEscape(incident)}
+	if update != "" {
+		parts = append(parts, url.QueryEscape(update))
+	}
+
+	return strings.Join(parts, ":")
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+type atomOut struct {
+	XMLName xml.Name       `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string         `xml:"title"`
+	ID      string         `xml:"id"`
+	Updated string         `xml:"updated"`
+	Links   []atomLinkOut  `xml:"link"`
+	Author  string         `xml:"author>name"`
+	Entries []atomEntryOut `xml:"entry"`
+}
+
+type atomLinkOut struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntryOut struct {
+	ID       string        `xml:"id"`
+	Title    string        `xml:"title"`
+	Updated  string        `xml:"updated"`
+	Links    []atomLinkOut `xml:"link"`
+	Category *atomCategory `xml:"category"`
+	Content  string        `xml:"content"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+func atomOutput(entries []feedEntry, opts FeedOptions, updated time.Time) atomOut {
+	feed := atomOut{
+		Title:   opts.Title,
+		ID:      firstOf(opts.Link, "urn:frain:feed"),
+		Updated: updated.UTC().Format(time.RFC3339),
+		Author:  "frain",
+	}
+	if opts.Link != "" {
+		feed.Links = []atomLinkOut{{Href: opts.Link, Rel: "self"}}
+	}
+
+	for _, e := range entries {
+		out := atomEntryOut{
+			ID:      e.guid,
+			Title:   e.title,
+			Updated: e.updated.UTC().Format(time.RFC3339),
+			Content: e.body,
+		}
+		if e.link != "" {
+			out.Links = []atomLinkOut{{Href: e.link}}
+		}
+		if e.impact != "" {
+			out.Category = &atomCategory{Term: e.impact}
+		}
+		feed.Entries = append(feed.Entries, out)
+	}
+
+	return feed
+}
+
+type rssOut struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title         string       `xml:"title"`
+		Link          string       `xml:"link"`
+		Description   string       `xml:"description"`
+		LastBuildDate string       `xml:"lastBuildDate"`
+		Items         []rssItemOut `xml:"item"`
+	} `xml:"channel"`
+}
+
+type rssItemOut struct {
+	Title string `xml:"title"`
+	Link  string `xml:"link,omitempty"`
+	GUID  struct {
+		Value       string `xml:",chardata"`
+		IsPermaLink bool   `xml:"isPermaLink,attr"`
+	} `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Category    string `xml:"category,omitempty"`
+	Description string `xml:"description"`
+}
+
+func rssOutput(entries []feedEntry, opts FeedOptions, updated time.Time) rssOut {
+	feed := rssOut{Version: "2.0"}
+	feed.Channel.Title = opts.Title
+	feed.Channel.Link = opts.Link
+	feed.Channel.Description = "Incidents and incident updates reported by frain"
+	feed.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+
+	for _, e := range entries {
+		out := rssItemOut{
+			Title:       e.title,
+			Link:        e.link,
+			PubDate:     e.updated.UTC().Format(time.RFC1123Z),
+			Category:    e.impact,
+			Description: e.body,
+		}
+		out.GUID.Value = e.guid
+		feed.Channel.Items = append(feed.Channel.Items, out)
+	}
+
+	return feed
+}
+
+// FeedReport is a construct to display the incidents of a page as an Atom or RSS feed
+type FeedReport struct {
+	Data *Page
+	// Format is FeedAtom or FeedRSS
+	Format string
+}
+
+// Incidents implements the Report interface
+func (f FeedReport) Incidents(w io.Writer, opts Options) error {
+	return WriteFeed(w, f.Format, []*Service{f.Data.Service}, FeedOptions{
+		Title: fmt.Sprintf("%s incidents", title(f.Data.Service.Name)),
+		Now:   opts.Now,
+	})
+}
+
+// Components implements the Report interface. Components have no feed, so it always
+// fails.
+func (f FeedReport) Components(w io.Writer, opts Options) error {
+	return errComponentsFeed
+}
+
+// All implements the Report interface, writing the incidents only
+func (f FeedReport) All(w io.Writer, opts Options) error {
+	return f.Incidents(w, opts)
+}
+
+// FeedHandler serves the feed of the services returned by fetch, as Atom or, with the
+// format=rss query parameter, as RSS
+func FeedHandler(fetch func() []*Service, opts FeedOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = FeedAtom
+		}
+
+		contentType := "application/atom+xml; charset=utf-8"
+		switch strings.ToLower(format) {
+		case FeedAtom:
+		case FeedRSS:
+			contentType = "application/rss+xml; charset=utf-8"
+		default:
+			http.Error(w, errFeedFormat.Error(), http.StatusBadRequest)
+			return
+		}
+
+		o := opts
+		if o.Link == "" {
+			scheme := "http"
+			if r.TLS != nil {
+				scheme = "https"
+			}
+			o.Link = fmt.Sprintf("%s://%s%s", scheme, r.Host, r.URL.RequestURI())
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		if err := WriteFeed(w, format, fetch(), o); err != nil {
+			log.Printf("frain: writing the feed to %s: %v", r.RemoteAddr, err)
+		}
+	})
+}
@@ -0,0 +1,157 @@
+package frain
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func feedServices() []*Service {
+	at := func(hour int) time.Time { return time.Date(2019, 6, 3, hour, 0, 0, 0, time.UTC) }
+
+	return []*Service{
+		{
+			Name: "github",
+			Incidents: []Incident{{
+				ID: "i1", IncidentID: "gh1", Name: "Delayed webhooks", Impact: "minor", Status: "monitoring",
+				Shortlink: "https://stspg.io/gh1",
+				IncidentUpdates: []IncidentUpdate{
+					{ID: "u2", IncidentUpdateID: "gh1-2", Status: "monitoring", Body: "A fix has been deployed.", CreatedAt: at(9)},
+					{ID: "u1", IncidentUpdateID: "gh1-1", Status: "investigating", Body: "We are investigating.", CreatedAt: at(8)},
+				},
+			}},
+		},
+		nil,
+		{
+			Name:          "fastly",
+			StatusPageURL: "https://status.fastly.com",
+			Incidents: []Incident{
+				{ID: "f1", Name: "Purge delays", Impact: "major", Status: "identified", UpdatedAt: at(10)},
+			},
+		},
+	}
+}
+
+func TestWriteAtomFeed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFeed(&buf, FeedAtom, feedServices(), FeedOptions{Title: "Vendors", Link: "http://example.com/feed.xml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var feed struct {
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+		Entries []struct {
+			ID    string `xml:"id"`
+			Title string `xml:"title"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid feed: %v\n%s", err, buf.String())
+	}
+	if feed.Title != "Vendors" || feed.Updated != "2019-06-03T10:00:00Z" {
+		t.Errorf("unexpected feed %+v", feed)
+	}
+
+	ids := []string{"urn:frain:fastly:f1", "urn:frain:github:gh1:gh1-2", "urn:frain:github:gh1:gh1-1"}
+	if len(feed.Entries) != len(ids) {
+		t.Fatalf("expected %d entries, got %+v", len(ids), feed.Entries)
+	}
+	for j, id := range ids {
+		if feed.Entries[j].ID != id {
+			t.Errorf("entry %d: expected id %s, got %s", j, id, feed.Entries[j].ID)
+		}
+	}
+	if feed.Entries[1].Title != "[Monitoring] Github: Delayed webhooks" {
+		t.Errorf("unexpected title %q", feed.Entries[1].Title)
+	}
+}
+
+func TestWriteRSSFeed(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteFeed(&buf, FeedRSS, feedServices(), FeedOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// frain reads its own feeds
+	s, err := ParseFeed(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Incidents) != 2 {
+		t.Fatalf("expected 2 incidents, got %+v", s.Incidents)
+	}
+	webhooks := s.Incidents[1]
+	if webhooks.Name != "Github: Delayed webhooks" || webhooks.Status != "monitoring" || len(webhooks.IncidentUpdates) != 2 {
+		t.Errorf("unexpected incident %+v", webhooks)
+	}
+	if webhooks.IncidentUpdates[0].ID != "urn:frain:github:gh1:gh1-2" {
+		t.Errorf("expected the update GUID, got %s", webhooks.IncidentUpdates[0].ID)
+	}
+
+	if err := WriteFeed(&buf, "json", nil, FeedOptions{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestFeedMaxAge(t *testing.T) {
+	var buf bytes.Buffer
+	opts := FeedOptions{Now: time.Date(2019, 6, 3, 10, 30, 0, 0, time.UTC), MaxAge: 90 * time.Minute}
+	if err := WriteFeed(&buf, FeedAtom, feedServices(), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	// the first update of the webhooks incident is too old, the second is not
+	if out := buf.String(); strings.Count(out, "<entry>") != 2 || strings.Contains(out, "gh1-1") || !strings.Contains(out, "gh1-2") {
+		t.Errorf("expected the updates of the last 90 minutes, got\n%s", out)
+	}
+}
+
+func TestFeedGUIDEscaping(t *testing.T) {
+	guid := feedGUID("Mailgun", "https://status.mailgun.com/incidents/mg1", "")
+	if guid != "urn:frain:mailgun:https%3A%2F%2Fstatus.mailgun.com%2Fincidents%2Fmg1" {
+		t.Errorf("unexpected guid %s", guid)
+	}
+}
+
+func TestFeedHandler(t *testing.T) {
+	h := FeedHandler(feedServices, FeedOptions{})
+
+	for format, contentType := range map[string]string{"": "application/atom+xml", "rss": "application/rss+xml"} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", "/feed.xml?format="+format, nil))
+		body, _ := ioutil.ReadAll(w.Body)
+		if w.Code != 200 || !strings.HasPrefix(w.Header().Get("Content-Type"), contentType) {
+			t.Errorf("%q: unexpected response %d %s", format, w.Code, w.Header().Get("Content-Type"))
+		}
+		if !bytes.Contains(body, []byte("urn:frain:github:gh1:gh1-1")) {
+			t.Errorf("%q: expected the incident updates, got\n%s", format, body)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/feed.xml?format=json", nil))
+	if w.Code != 400 {
+		t.Errorf("expected a bad request for an unknown format, got %d", w.Code)
+	}
+}
+
+func TestFeedReport(t *testing.T) {
+	page := &Page{Name: "github", Service: feedServices()[0]}
+	r := FeedReport{Data: page, Format: FeedAtom}
+
+	var buf bytes.Buffer
+	if err := r.All(&buf, Options{}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "<title>Github incidents</title>") {
+		t.Errorf("unexpected feed\n%s", buf.String())
+	}
+	if err := r.Components(&buf, Options{}); err == nil {
+		t.Error("expected components to have no feed")
+	}
+}
@@ -0,0 +1,81 @@
+package frain
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template is a construct to display the page information through a user defined Go
+// text/template. The template is executed with the Page as its data.
+type Template struct {
+	Data *Page
+	Tmpl *template.Template
+}
+
+// NewTemplate parses text into a template report with the frain helper functions
+// available
+func NewTemplate(data *Page, name, text string) (*Template, error) {
+	tmpl, err := template.New(name).Funcs(TemplateFuncs()).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Template{Data: data, Tmpl: tmpl}, nil
+}
+
+// Incidents implements the Report interface
+func (t Template) Incidents(w io.Writer, opts Options) error {
+	return t.Tmpl.Execute(w, t.Data)
+}
+
+// Components implements the Report interface
+func (t Template) Components(w io.Writer, opts Options) error {
+	return t.Tmpl.Execute(w, t.Data)
+}
+
+// All implements the Report interface
+func (t Template) All(w io.Writer, opts Options) error {
+	return t.Tmpl.Execute(w, t.Data)
+}
+
+// TemplateFuncs returns the helper functions available to template reports:
+//
+//	timeago t        how long ago t was, e.g. "3 hours ago"
+//	wrap n s         s word wrapped at n columns
+//	pad n s          s truncated with dots or filled with spaces to exactly n columns
+//	colour s         s coloured according to the status it names
+//	duration a b     time elapsed between a and b, or between a and now when b is zero
+//	upper s          s in upper case
+//	json v           v encoded as JSON
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"timeago": func(t time.Time) string {
+			s, err := TimeAgo(t, time.Now())
+			if err != nil {
+				return ""
+			}
+			return s
+		},
+		"wrap": func(width int, s string) string {
+			return strings.Join(wrap(s, width), "\n")
+		},
+		"pad": func(width int, s string) string {
+			return fill(truncate(s, width), width)
+		},
+		"colour": Render,
+		"duration": func(start, end time.Time) string {
+			if end.IsZero() {
+				end = time.Now()
+			}
+			return end.Sub(start).Round(time.Second).String()
+		},
+		"upper": strings.ToUpper,
+		"json": func(v interface{}) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package frain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestTemplate(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	page := &Page{
+		Name: "github",
+		Service: &Service{
+			Name:      "github",
+			Indicator: "minor",
+			Components: []Component{
+				{Name: "API Requests", Status: "operational"},
+				{Name: "Webhooks", Status: "partial_outage"},
+			},
+		},
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{`{{upper .Name}} {{.Service.Indicator}}`, "GITHUB minor"},
+		{`{{range .Service.Components}}[{{pad 8 .Name}}]{{end}}`, "[API R...][Webhooks]"},
+		{`{{range .Service.Components}}{{colour .Status}};{{end}}`, "operational;partial_outage;"},
+		{`{{wrap 7 "Hello there world"}}`, "Hello\nthere\nworld"},
+		{`{{json .Service.Indicator}}`, `"minor"`},
+	}
+
+	for _, tt := range tests {
+		report, err := NewTemplate(page, "test", tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := report.All(&buf, Options{}); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("expected %q, got %q", tt.want, got)
+		}
+	}
+}
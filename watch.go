@@ -0,0 +1,209 @@
+package frain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Event types reported when comparing two checks of a service
+const (
+	EventService   = "service"
+	EventComponent = "component"
+	EventIncident  = "incident"
+)
+
+// Event describes a change in the status of a service, one of its components or one of
+// its incidents between two consecutive checks
+type Event struct {
+	Type      string    `json:"type"`
+	Service   string    `json:"service"`
+	Component string    `json:"component,omitempty"`
+	Incident  string    `json:"incident,omitempty"`
+	OldStatus string    `json:"oldStatus"`
+	NewStatus string    `json:"newStatus"`
+	Time      time.Time `json:"time"`
+}
+
+// String returns a single line description of the event
+func (e Event) String() string {
+	subject := e.Service
+	switch e.Type {
+	case EventComponent:
+		subject = fmt.Sprintf("%s component %q", e.Service, e.Component)
+	case EventIncident:
+		subject = fmt.Sprintf("%s incident %q", e.Service, e.Incident)
+	}
+
+	return fmt.Sprintf("%s: %s -> %s", subject, orDash(e.OldStatus), orDash(e.NewStatus))
+}
+
+// Changes compares two checks of the same service and returns the events describing
+// how its status, components and incidents changed, a component or incident gone from
+// the new check having no new status. A nil old service yields no events.
+func Changes(old, new *Service) []Event {
+	if old == nil || new == nil {
+		return nil
+	}
+
+	now := time.Now()
+	var events []Event
+
+	if old.Indicator != new.Indicator || old.Status != new.Status {
+		events = append(events, Event{
+			Type:      EventService,
+			Service:   new.Name,
+			OldStatus: serviceStatus(old),
+			NewStatus: serviceStatus(new),
+			Time:      now,
+		})
+	}
+
+	oldComponents := map[string]string{}
+	for _, c := range old.Components {
+		oldComponents[c.Name] = c.Status
+	}
+	newComponents := map[string]bool{}
+	for _, c := range new.Components {
+		newComponents[c.Name] = true
+		if status := oldComponents[c.Name]; status != c.Status {
+			events = append(events, Event{
+				Type:      EventComponent,
+				Service:   new.Name,
+				Component: c.Name,
+				OldStatus: status,
+				NewStatus: c.Status,
+				Time:      now,
+			})
+		}
+	}
+	for _, c := range old.Components {
+		if !newComponents[c.Name] {
+			events = append(events, Event{
+				Type:      EventComponent,
+				Service:   new.Name,
+				Component: c.Name,
+				OldStatus: c.Status,
+				Time:      now,
+			})
+		}
+	}
+
+	oldIncidents := map[string]string{}
+	for _, i := range old.Incidents {
+		oldIncidents[i.ID] = i.Status
+	}
+	newIncidents := map[string]bool{}
+	for _, i := range new.Incidents {
+		newIncidents[i.ID] = true
+		if status, ok := oldIncidents[i.ID]; !ok || status != i.Status {
+			events = append(events, Event{
+				Type:      EventIncident,
+				Service:   new.Name,
+				Incident:  i.Name,
+				OldStatus: status,
+				NewStatus: i.Status,
+				Time:      now,
+			})
+		}
+	}
+	for _, i := range old.Incidents {
+		if !newIncidents[i.ID] {
+			events = append(events, Event{
+				Type:      EventIncident,
+				Service:   new.Name,
+				Incident:  i.Name,
+				OldStatus: i.Status,
+				Time:      now,
+			})
+		}
+	}
+
+	return events
+}
+
+func serviceStatus(s *Service) string {
+	if s.Indicator != "" {
+		return s.Indicator
+	}
+
+	return s.Status
+}
+
+// DefaultHookTimeout is how long a hook may run when its Timeout is not set
+const DefaultHookTimeout = 30 * time.Second
+
+// Hook is a local command run whenever a watched service changes
+type Hook struct {
+	Command string
+	// Timeout is how long the command may run before it is killed, DefaultHookTimeout
+	// when zero
+	Timeout time.Duration
+}
+
+// Run executes the hook command through the shell. The event is passed as JSON on the
+// standard input and through FRAIN_* environment variables.
+func (h Hook) Run(e Event) ([]byte, error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", h.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", h.Command)
+	}
+
+	// the output goes to a file rather than a pipe, which a process started by the
+	// command and outliving it would keep open, so that a killed hook returns at once
+	out, err := ioutil.TempFile("", "frain-hook")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(out.Name())
+	defer out.Close()
+
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.Env = append(os.Environ(), e.Env()...)
+
+	err = cmd.Run()
+	output, _ := ioutil.ReadFile(out.Name())
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("hook %q killed after %v", h.Command, timeout)
+	}
+	if err != nil {
+		return output, fmt.Errorf("hook %q failed: %v", h.Command, err)
+	}
+
+	return output, nil
+}
+
+// Env returns the event as a list of FRAIN_* environment variables
+func (e Event) Env() []string {
+	return []string{
+		"FRAIN_EVENT=" + e.Type,
+		"FRAIN_SERVICE=" + e.Service,
+		"FRAIN_COMPONENT=" + e.Component,
+		"FRAIN_INCIDENT=" + e.Incident,
+		"FRAIN_OLD_STATUS=" + e.OldStatus,
+		"FRAIN_NEW_STATUS=" + e.NewStatus,
+		"FRAIN_TIME=" + e.Time.Format(time.RFC3339),
+	}
+}
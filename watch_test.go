@@ -0,0 +1,102 @@
+package frain
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChanges(t *testing.T) {
+	old := &Service{
+		Name:      "github",
+		Indicator: "none",
+		Components: []Component{
+			{Name: "API Requests", Status: "operational"},
+			{Name: "Webhooks", Status: "operational"},
+		},
+		Incidents: []Incident{
+			{ID: "1", Name: "Slow pages", Status: "investigating"},
+			{ID: "0", Name: "Planned upgrade", Status: "in_progress"},
+		},
+	}
+	new := &Service{
+		Name:      "github",
+		Indicator: "minor",
+		Components: []Component{
+			{Name: "API Requests", Status: "operational"},
+			{Name: "Webhooks", Status: "partial_outage"},
+		},
+		Incidents: []Incident{
+			{ID: "1", Name: "Slow pages", Status: "identified"},
+			{ID: "2", Name: "Webhook delays", Status: "investigating"},
+		},
+	}
+
+	want := []string{
+		"github: none -> minor",
+		`github component "Webhooks": operational -> partial_outage`,
+		`github incident "Slow pages": investigating -> identified`,
+		`github incident "Webhook delays": - -> investigating`,
+		`github incident "Planned upgrade": in_progress -> -`,
+	}
+
+	got := Changes(old, new)
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %d: %v", len(want), len(got), got)
+	}
+	for i, e := range got {
+		if e.String() != want[i] {
+			t.Errorf("expected %v, got %v", want[i], e.String())
+		}
+	}
+
+	if events := Changes(nil, new); len(events) != 0 {
+		t.Errorf("expected no events on first check, got %v", events)
+	}
+}
+
+func TestHookRun(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test relies on a POSIX shell")
+	}
+
+	e := Event{
+		Type:      EventComponent,
+		Service:   "github",
+		Component: "Webhooks",
+		OldStatus: "operational",
+		NewStatus: "major_outage",
+	}
+
+	out, err := Hook{Command: `echo "$FRAIN_SERVICE/$FRAIN_COMPONENT $FRAIN_OLD_STATUS $FRAIN_NEW_STATUS"; cat`}.Run(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.SplitN(string(out), "\n", 2)
+	if want := "github/Webhooks operational major_outage"; lines[0] != want {
+		t.Errorf("expected %v, got %v", want, lines[0])
+	}
+	if !strings.Contains(lines[1], `"newStatus":"major_outage"`) {
+		t.Errorf("expected event JSON on stdin, got %v", lines[1])
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook test relies on a POSIX shell")
+	}
+
+	start := time.Now()
+	out, err := Hook{Command: "echo started; sleep 10 & wait", Timeout: 100 * time.Millisecond}.Run(Event{})
+	if err == nil || !strings.Contains(err.Error(), "killed") {
+		t.Errorf("expected the hook to be killed, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the hook to be killed at once, took %v", elapsed)
+	}
+	if string(out) != "started\n" {
+		t.Errorf("expected the output so far, got %q", out)
+	}
+}